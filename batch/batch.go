@@ -0,0 +1,103 @@
+package batch
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultConcurrency 默认同时进行的请求数
+const DefaultConcurrency = 4
+
+// ErrSkipped FailFast 模式下 前面的请求出错后未发起的请求
+var ErrSkipped = errors.New("skipped after earlier failure")
+
+// Options 批量请求的并发与限速
+type Options struct {
+	Concurrency int           // 同时进行的请求数 0 时为 DefaultConcurrency
+	Interval    time.Duration // 两次请求开始之间的最小间隔 用于满足交易所的频率限制
+	FailFast    bool          // 出错后不再发起新的请求 未发起的返回 ErrSkipped
+}
+
+// Run 并发执行 fn(0) ... fn(n-1) 按下标顺序发起 返回的错误与下标一一对应
+func Run(n int, opts Options, fn func(i int) error) []error {
+	errs := make([]error, n)
+	if n == 0 {
+		return errs
+	}
+	workers := opts.Concurrency
+	if workers <= 0 {
+		workers = DefaultConcurrency
+	}
+	if workers > n {
+		workers = n
+	}
+
+	var (
+		mu     sync.Mutex
+		next   time.Time
+		failed bool
+		wg     sync.WaitGroup
+	)
+	// start 等到允许发起请求的时刻 FailFast 且已出错时返回 false
+	start := func() bool {
+		mu.Lock()
+		if failed && opts.FailFast {
+			mu.Unlock()
+			return false
+		}
+		now := time.Now()
+		at := next
+		if at.Before(now) {
+			at = now
+		}
+		next = at.Add(opts.Interval)
+		mu.Unlock()
+		time.Sleep(at.Sub(now))
+
+		mu.Lock()
+		defer mu.Unlock()
+		return !(failed && opts.FailFast)
+	}
+
+	indexes := make(chan int)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if !start() {
+					errs[i] = ErrSkipped
+					continue
+				}
+				if err := fn(i); err != nil {
+					errs[i] = err
+					mu.Lock()
+					failed = true
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return errs
+}
+
+// First 按下标顺序返回第一个错误 ErrSkipped 之外的优先
+func First(errs []error) error {
+	var skipped error
+	for _, err := range errs {
+		if err == ErrSkipped {
+			skipped = err
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return skipped
+}
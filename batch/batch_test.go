@@ -0,0 +1,84 @@
+package batch
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunOrderAndConcurrency(t *testing.T) {
+	var running, peak int32
+	out := make([]int, 20)
+	errs := Run(20, Options{Concurrency: 3}, func(i int) error {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		out[i] = i * i
+		if i == 7 {
+			return errors.New("seven")
+		}
+		return nil
+	})
+	if peak > 3 {
+		t.Fatalf("peak concurrency %d", peak)
+	}
+	for i, v := range out {
+		if v != i*i {
+			t.Fatalf("out[%d] = %d", i, v)
+		}
+	}
+	for i, err := range errs {
+		if (i == 7) != (err != nil) {
+			t.Fatalf("errs[%d] = %v", i, err)
+		}
+	}
+	if First(errs).Error() != "seven" {
+		t.Fatal(First(errs))
+	}
+}
+
+func TestRunFailFast(t *testing.T) {
+	var calls int32
+	errs := Run(10, Options{Concurrency: 1, FailFast: true}, func(i int) error {
+		atomic.AddInt32(&calls, 1)
+		if i == 2 {
+			return errors.New("boom")
+		}
+		return nil
+	})
+	if calls != 3 {
+		t.Fatalf("calls %d", calls)
+	}
+	if errs[1] != nil || errs[2] == nil || errs[3] != ErrSkipped || errs[9] != ErrSkipped {
+		t.Fatal(errs)
+	}
+	if First(errs).Error() != "boom" {
+		t.Fatal(First(errs))
+	}
+}
+
+func TestRunInterval(t *testing.T) {
+	start := time.Now()
+	Run(5, Options{Concurrency: 5, Interval: 10 * time.Millisecond}, func(i int) error { return nil })
+	if d := time.Since(start); d < 40*time.Millisecond {
+		t.Fatalf("5 requests took only %v", d)
+	}
+}
+
+func TestPacer(t *testing.T) {
+	p := Pacer{Interval: 20 * time.Millisecond}
+	start := time.Now()
+	p.Wait()
+	p.Wait()
+	p.Wait()
+	if d := time.Since(start); d < 40*time.Millisecond {
+		t.Fatalf("pacer waited only %v", d)
+	}
+}
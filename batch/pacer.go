@@ -0,0 +1,19 @@
+package batch
+
+import "time"
+
+// Pacer 保证两次请求之间至少间隔 Interval 用于顺序翻页 不能并发使用
+type Pacer struct {
+	Interval time.Duration
+	last     time.Time
+}
+
+// Wait 距上次调用不足 Interval 时等待
+func (p *Pacer) Wait() {
+	if !p.last.IsZero() {
+		if d := p.Interval - time.Since(p.last); d > 0 {
+			time.Sleep(d)
+		}
+	}
+	p.last = time.Now()
+}
@@ -0,0 +1,263 @@
+package bibox
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+//Command A Typed Bibox Command That Can Be Queued In A Batch
+type Command interface {
+	path() string
+	cmd() *CMD
+	decode(result json.RawMessage) error
+	fail(err error)
+}
+
+//validator Commands That Can Be Rejected Before Sending
+type validator interface {
+	validate() error
+}
+
+//commandError Error Of A Single Command After Batch.Do
+type commandError struct {
+	err error
+}
+
+//Err Error Of This Command, nil When It Succeeded
+func (c *commandError) Err() error {
+	return c.err
+}
+
+func (c *commandError) fail(err error) {
+	c.err = err
+}
+
+//DepthCommand Queued api/depth
+type DepthCommand struct {
+	commandError
+	Pair   string
+	Size   int
+	Result *DepthResult
+}
+
+func (c *DepthCommand) path() string { return pathMdata }
+func (c *DepthCommand) cmd() *CMD    { return depthCMD(c.Pair, c.Size) }
+func (c *DepthCommand) decode(result json.RawMessage) error {
+	c.Result = new(DepthResult)
+	return json.Unmarshal(result, c.Result)
+}
+
+//AssetsCommand Queued transfer/assets
+type AssetsCommand struct {
+	commandError
+	Result *AssetsResult
+}
+
+func (c *AssetsCommand) path() string { return pathTransfer }
+func (c *AssetsCommand) cmd() *CMD    { return assetsCMD() }
+func (c *AssetsCommand) decode(result json.RawMessage) error {
+	c.Result = new(AssetsResult)
+	return json.Unmarshal(result, c.Result)
+}
+
+//TradeCommand Queued orderpending/trade
+type TradeCommand struct {
+	commandError
+	Body   *TradeBody
+	Result *TradeResult
+}
+
+func (c *TradeCommand) path() string    { return pathOrderPending }
+func (c *TradeCommand) cmd() *CMD       { return tradeCMD(c.Body) }
+func (c *TradeCommand) validate() error { return c.Body.Validate() }
+func (c *TradeCommand) decode(result json.RawMessage) error {
+	c.Result = new(TradeResult)
+	return json.Unmarshal(result, c.Result)
+}
+
+//CancelTradeCommand Queued orderpending/cancelTrade
+type CancelTradeCommand struct {
+	commandError
+	ID     uint64
+	Result *CancelTradeResult
+}
+
+func (c *CancelTradeCommand) path() string { return pathOrderPending }
+func (c *CancelTradeCommand) cmd() *CMD    { return cancelTradeCMD(c.ID) }
+func (c *CancelTradeCommand) decode(result json.RawMessage) error {
+	c.Result = new(CancelTradeResult)
+	return json.Unmarshal(result, c.Result)
+}
+
+//PendingCommand Queued orderpending/orderPendingList Or orderpending/pendingHistoryList
+type PendingCommand struct {
+	commandError
+	Body    *PendingBody
+	History bool
+	Result  *PendingResult
+}
+
+func (c *PendingCommand) path() string    { return pathOrderPending }
+func (c *PendingCommand) validate() error { return c.Body.Validate() }
+func (c *PendingCommand) cmd() *CMD {
+	if c.History {
+		return pendingCMD(cmdPendingHistoryList, c.Body)
+	}
+	return pendingCMD(cmdPendingList, c.Body)
+}
+func (c *PendingCommand) decode(result json.RawMessage) error {
+	c.Result = new(PendingResult)
+	return json.Unmarshal(result, c.Result)
+}
+
+//Batch Queue Commands And Send Them As Signed Requests.
+//Bibox Routes Commands By Endpoint (mdata, transfer, orderpending),
+//So Commands Are Grouped Into One Request Per Endpoint
+type Batch struct {
+	bs       *BiboxService
+	commands []Command
+}
+
+//NewBatch New An Empty Batch
+func (bs *BiboxService) NewBatch() *Batch {
+	return &Batch{bs: bs}
+}
+
+//Add Queue A Command
+func (b *Batch) Add(cmd Command) {
+	b.commands = append(b.commands, cmd)
+}
+
+//Len Number Of Queued Commands
+func (b *Batch) Len() int {
+	return len(b.commands)
+}
+
+//Depth Queue A Depth Command
+func (b *Batch) Depth(pair string, size int) *DepthCommand {
+	c := &DepthCommand{Pair: pair, Size: size}
+	b.Add(c)
+	return c
+}
+
+//Assets Queue An Assets Command
+func (b *Batch) Assets() *AssetsCommand {
+	c := &AssetsCommand{}
+	b.Add(c)
+	return c
+}
+
+//Trade Queue A Trade Command
+func (b *Batch) Trade(tradeBody *TradeBody) *TradeCommand {
+	c := &TradeCommand{Body: tradeBody}
+	b.Add(c)
+	return c
+}
+
+//CancelTrade Queue A Cancel Trade Command
+func (b *Batch) CancelTrade(id uint64) *CancelTradeCommand {
+	c := &CancelTradeCommand{ID: id}
+	b.Add(c)
+	return c
+}
+
+//CurrentPending Queue A Current Pending List Command
+func (b *Batch) CurrentPending(pendingBody *PendingBody) *PendingCommand {
+	c := &PendingCommand{Body: pendingBody}
+	b.Add(c)
+	return c
+}
+
+//HistoryPending Queue A History Pending List Command
+func (b *Batch) HistoryPending(pendingBody *PendingBody) *PendingCommand {
+	c := &PendingCommand{Body: pendingBody, History: true}
+	b.Add(c)
+	return c
+}
+
+//Do Send All Queued Commands, One Request Per Endpoint In Order Of First Use.
+//Each Command Gets Its Own Result Or Err, Invalid Commands Fail Without Being Sent
+//And Keep Their Validation Error. A Failed Request Only Fails The Commands Of Its Endpoint.
+//The Returned Error Is The First Request Level Error
+func (b *Batch) Do() error {
+	paths := make([]string, 0)
+	groups := make(map[string][]Command)
+	for _, c := range b.commands {
+		if v, ok := c.(validator); ok {
+			if err := v.validate(); err != nil {
+				c.fail(err)
+				continue
+			}
+		}
+		if _, ok := groups[c.path()]; !ok {
+			paths = append(paths, c.path())
+		}
+		groups[c.path()] = append(groups[c.path()], c)
+	}
+
+	var firstErr error
+	for _, path := range paths {
+		group := groups[path]
+		cmds := make([]*CMD, 0, len(group))
+		for index, c := range group {
+			cmd := c.cmd()
+			cmd.Index = index + 1
+			cmds = append(cmds, cmd)
+		}
+		results, err := b.bs.send(path, cmds)
+		if err == nil {
+			err = dispatch(group, results)
+		}
+		if err != nil {
+			for _, c := range group {
+				c.fail(err)
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+//dispatch Route Each Result To Its Command By index, Falling Back To Position
+func dispatch(group []Command, results *Results) error {
+	if results.Error != nil && len(results.Result) == 0 {
+		return errors.New(results.Error.Msg)
+	}
+	seen := make([]bool, len(group))
+	for pos, raw := range results.Result {
+		var entry struct {
+			Error *Error `json:"error"`
+			Index int    `json:"index"`
+		}
+		if err := json.Unmarshal(raw, &entry); err != nil {
+			return err
+		}
+		i := pos
+		if entry.Index >= 1 && entry.Index <= len(group) {
+			i = entry.Index - 1
+		}
+		if i >= len(group) || seen[i] {
+			continue
+		}
+		seen[i] = true
+		if entry.Error != nil {
+			group[i].fail(errors.New(entry.Error.Msg))
+			continue
+		}
+		if err := group[i].decode(raw); err != nil {
+			group[i].fail(err)
+		}
+	}
+	for i, ok := range seen {
+		if !ok {
+			if results.Error != nil {
+				group[i].fail(errors.New(results.Error.Msg))
+			} else {
+				group[i].fail(errors.New("no result for command " + group[i].cmd().Cmd))
+			}
+		}
+	}
+	return nil
+}
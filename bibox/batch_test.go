@@ -0,0 +1,119 @@
+package bibox
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBatchDispatch(t *testing.T) {
+	trade := &TradeCommand{Body: &TradeBody{Pair: "BIX_ETH"}}
+	cancel := &CancelTradeCommand{ID: 1}
+	pending := &PendingCommand{Body: &PendingBody{Page: 1, Size: 10}}
+	group := []Command{trade, cancel, pending}
+
+	body := `{"result":[
+		{"result":"OK","cmd":"orderpending/cancelTrade","index":2},
+		{"error":{"code":"2091","msg":"order not exist"},"cmd":"orderpending/trade","index":1}
+	]}`
+	var results Results
+	if err := json.Unmarshal([]byte(body), &results); err != nil {
+		t.Fatal(err)
+	}
+	if err := dispatch(group, &results); err != nil {
+		t.Fatal(err)
+	}
+	if trade.Err() == nil || trade.Err().Error() != "order not exist" {
+		t.Fatal("trade should carry its own error", trade.Err())
+	}
+	if cancel.Err() != nil || cancel.Result.Result != "OK" {
+		t.Fatal("cancel should succeed", cancel.Err())
+	}
+	if pending.Err() == nil {
+		t.Fatal("pending without result should fail")
+	}
+}
+
+func TestBatchDispatchRequestError(t *testing.T) {
+	results := &Results{Error: &Error{Code: "3012", Msg: "invalid apikey"}}
+	if err := dispatch([]Command{&AssetsCommand{}}, results); err == nil {
+		t.Fatal("request level error should be returned")
+	}
+}
+
+func TestBatchGroupsEndpoints(t *testing.T) {
+	requests := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		var params Params
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			t.Error(err)
+		}
+		var cmds []CMD
+		if err := json.Unmarshal([]byte(params.Cmds), &cmds); err != nil {
+			t.Error(err)
+		}
+		switch r.URL.Path {
+		case "/v1/mdata":
+			fmt.Fprintf(w, `{"result":[{"result":{"pair":"BIX_BTC"},"cmd":"%s","index":%d}]}`, cmds[0].Cmd, cmds[0].Index)
+		case "/v1/transfer":
+			fmt.Fprint(w, `{"error":{"code":"3012","msg":"invalid apikey"}}`)
+		case "/v1/orderpending":
+			if len(cmds) != 2 {
+				t.Error("orderpending commands", len(cmds))
+			}
+			fmt.Fprint(w, `{"result":[{"result":"OK","index":2},{"result":"OK","index":1}]}`)
+		}
+	}))
+	defer server.Close()
+
+	s, _ := NewBiboxService(server.URL+"/", "", "")
+	b := s.NewBatch()
+	cancel := b.CancelTrade(1)
+	depth := b.Depth("BIX_BTC", 5)
+	invalid := b.Trade(&TradeBody{Pair: "BIX_ETH", OrderType: OrderTypeLimit, OrderSide: 3, Price: 1, Amount: 1})
+	assets := b.Assets()
+	other := b.CancelTrade(2)
+	if err := b.Do(); err == nil || err.Error() != "invalid apikey" {
+		t.Fatal("transfer request error should be returned", err)
+	}
+	if len(requests) != 3 || requests["/v1/orderpending"] != 1 {
+		t.Fatal("one request per endpoint", requests)
+	}
+	if depth.Err() != nil || depth.Result.Result.Pair != "BIX_BTC" {
+		t.Fatal("depth should succeed", depth.Err())
+	}
+	if cancel.Err() != nil || other.Err() != nil || cancel.Result.Result != "OK" {
+		t.Fatal("cancels should succeed", cancel.Err(), other.Err())
+	}
+	if assets.Err() == nil {
+		t.Fatal("assets should carry the transfer request error")
+	}
+	if invalid.Err() == nil || invalid.Err().Error() == "invalid apikey" {
+		t.Fatal("invalid trade should keep its validation error", invalid.Err())
+	}
+}
+
+func TestBatchDispatchBadEntry(t *testing.T) {
+	results := new(Results)
+	if err := json.Unmarshal([]byte(`{"result":[{"index":"one"}]}`), results); err != nil {
+		t.Fatal(err)
+	}
+	if err := dispatch([]Command{&AssetsCommand{}}, results); err == nil {
+		t.Fatal("undecodable entry should be returned")
+	}
+}
+
+func TestBatchRejectsInvalidTrade(t *testing.T) {
+	s, _ := NewBiboxService("https://api.bibox.com/", "", "")
+	b := s.NewBatch()
+	trade := b.Trade(&TradeBody{Pair: "BIX_ETH", OrderType: OrderTypeLimit, OrderSide: 3, Price: 1, Amount: 1})
+	if err := b.Do(); err != nil {
+		t.Fatal(err)
+	}
+	if trade.Err() == nil {
+		t.Fatal("invalid side should be rejected before sending")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 )
@@ -25,24 +26,17 @@ func NewBiboxService(url, apiKey, secret string) (*BiboxService, error) {
 	return s, nil
 }
 
-//GetAssets Get User Bibox Assets
-func (bs *BiboxService) GetAssets() (*AssetsResult, error) {
-	url := bs.URL + "v1/transfer"
-	secret := bs.SecretKey
+//send Sign Commands And Post Them To Path
+func (bs *BiboxService) send(path string, cmds []*CMD) (*Results, error) {
+	url := bs.URL + path
 	params := new(Params)
 	params.APIKey = bs.APIKey
-	cmd := new(CMD)
-	cmd.Cmd = "transfer/assets"
-	cmd.Body = make(map[string]interface{})
-	cmd.Body["select"] = 1
-	cmds := make([]*CMD, 0)
-	cmds = append(cmds, cmd)
 	dataCmds, err := json.Marshal(cmds)
 	if err != nil {
 		return nil, err
 	}
 	params.Cmds = string(dataCmds)
-	params.Sign = Hmac(secret, params.Cmds)
+	params.Sign = Hmac(bs.SecretKey, params.Cmds)
 	dataParams, err := json.Marshal(params)
 	if err != nil {
 		return nil, err
@@ -64,18 +58,32 @@ func (bs *BiboxService) GetAssets() (*AssetsResult, error) {
 		return nil, err
 	}
 	var results Results
-	var assetsResult AssetsResult
 	err = json.Unmarshal(body, &results)
 	if err != nil {
-		return nil, err
+		return nil, errors.New(err.Error() + ":" + string(body))
+	}
+	return &results, nil
+}
+
+//sendOne Send A Single Command And Decode Its Result Into Target
+func (bs *BiboxService) sendOne(path string, cmd *CMD, target interface{}, name string) error {
+	results, err := bs.send(path, []*CMD{cmd})
+	if err != nil {
+		return err
 	}
 	if results.Error != nil {
-		return nil, errors.New(results.Error.Msg)
+		return errors.New(results.Error.Msg)
 	}
 	if len(results.Result) != 1 {
-		return nil, errors.New("get assets result length invalid")
+		return errors.New(name + " result length invalid")
 	}
-	err = json.Unmarshal(results.Result[0], &assetsResult)
+	return json.Unmarshal(results.Result[0], target)
+}
+
+//GetAssets Get User Bibox Assets
+func (bs *BiboxService) GetAssets() (*AssetsResult, error) {
+	var assetsResult AssetsResult
+	err := bs.sendOne(pathTransfer, assetsCMD(), &assetsResult, "get assets")
 	if err != nil {
 		return nil, err
 	}
@@ -84,56 +92,8 @@ func (bs *BiboxService) GetAssets() (*AssetsResult, error) {
 
 //GetDepth Get Market Depth Data
 func (bs *BiboxService) GetDepth(pair string, size int) (*DepthResult, error) {
-	url := bs.URL + "v1/mdata"
-	secret := bs.SecretKey
-	params := new(Params)
-	params.APIKey = bs.APIKey
-	cmd := new(CMD)
-	cmd.Cmd = "api/depth"
-	cmd.Body = make(map[string]interface{})
-	cmd.Body["pair"] = pair
-	cmd.Body["size"] = size
-	cmds := make([]*CMD, 0)
-	cmds = append(cmds, cmd)
-	dataCmds, err := json.Marshal(cmds)
-	if err != nil {
-		return nil, err
-	}
-	params.Cmds = string(dataCmds)
-	params.Sign = Hmac(secret, params.Cmds)
-	dataParams, err := json.Marshal(params)
-	if err != nil {
-		return nil, err
-	}
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(dataParams))
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	var results Results
 	var depthResult DepthResult
-	err = json.Unmarshal(body, &results)
-	if err != nil {
-		return nil, err
-	}
-	if results.Error != nil {
-		return nil, errors.New(results.Error.Msg)
-	}
-	if len(results.Result) != 1 {
-		return nil, errors.New("get depth result length invalid")
-	}
-	err = json.Unmarshal(results.Result[0], &depthResult)
+	err := bs.sendOne(pathMdata, depthCMD(pair, size), &depthResult, "get depth")
 	if err != nil {
 		return nil, err
 	}
@@ -142,57 +102,21 @@ func (bs *BiboxService) GetDepth(pair string, size int) (*DepthResult, error) {
 
 //GetBatchDepth Get Market Batch Depth Data
 func (bs *BiboxService) GetBatchDepth(pairs []string, size int) ([]*DepthResult, error) {
-	url := bs.URL + "v1/mdata"
-	secret := bs.SecretKey
-	params := new(Params)
-	params.APIKey = bs.APIKey
 	cmds := make([]*CMD, 0)
 	for _, pair := range pairs {
-		cmd := new(CMD)
-		cmd.Cmd = "api/depth"
-		cmd.Body = make(map[string]interface{})
-		cmd.Body["pair"] = pair
-		cmd.Body["size"] = size
-		cmds = append(cmds, cmd)
-	}
-	dataCmds, err := json.Marshal(cmds)
-	if err != nil {
-		return nil, err
-	}
-	params.Cmds = string(dataCmds)
-	params.Sign = Hmac(secret, params.Cmds)
-	dataParams, err := json.Marshal(params)
-	if err != nil {
-		return nil, err
-	}
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(dataParams))
-	if err != nil {
-		return nil, err
+		cmds = append(cmds, depthCMD(pair, size))
 	}
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	results, err := bs.send(pathMdata, cmds)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, errors.New(err.Error() + ":" + string(body))
-	}
-	var results Results
-	var depthResults []*DepthResult
-	err = json.Unmarshal(body, &results)
-	if err != nil {
-		return nil, errors.New(err.Error() + ":" + string(body))
-	}
 	if results.Error != nil {
 		return nil, errors.New(results.Error.Msg)
 	}
 	if len(results.Result) <= 0 {
 		return nil, errors.New("get depth result length invalid")
 	}
+	var depthResults []*DepthResult
 	for _, result := range results.Result {
 		var depthResult DepthResult
 		err = json.Unmarshal(result, &depthResult)
@@ -207,65 +131,83 @@ func (bs *BiboxService) GetBatchDepth(pairs []string, size int) ([]*DepthResult,
 	return depthResults, nil
 }
 
-//Trade Trade in Bibox
-func (bs *BiboxService) Trade(tradeBody *TradeBody) (*TradeResult, error) {
-	url := bs.URL + "v1/orderpending"
-	secret := bs.SecretKey
-	params := new(Params)
-	params.APIKey = bs.APIKey
-	cmd := new(CMD)
-	cmd.Cmd = "orderpending/trade"
-	cmd.Index = 1
-	cmd.Body = make(map[string]interface{})
-	cmd.Body["pair"] = tradeBody.Pair
-	cmd.Body["account_type"] = tradeBody.AccountType
-	cmd.Body["order_type"] = tradeBody.OrderType
-	cmd.Body["order_side"] = tradeBody.OrderSide
-	cmd.Body["pay_bix"] = tradeBody.PayBix
-	cmd.Body["price"] = tradeBody.Price
-	cmd.Body["amount"] = tradeBody.Amount
-	cmd.Body["money"] = tradeBody.Money
-	cmds := make([]*CMD, 0)
-	cmds = append(cmds, cmd)
-	dataCmds, err := json.Marshal(cmds)
+//Kline Periods
+const (
+	Period1Min   = "1min"
+	Period3Min   = "3min"
+	Period5Min   = "5min"
+	Period15Min  = "15min"
+	Period30Min  = "30min"
+	Period1Hour  = "1hour"
+	Period2Hour  = "2hour"
+	Period4Hour  = "4hour"
+	Period6Hour  = "6hour"
+	Period12Hour = "12hour"
+	PeriodDay    = "day"
+	PeriodWeek   = "week"
+)
+
+//GetPairList Get All Trading Pairs
+func (bs *BiboxService) GetPairList() (*PairListResult, error) {
+	var pairListResult PairListResult
+	err := bs.sendOne(pathMdata, marketCMD(cmdPairList, "", 0), &pairListResult, "get pair list")
 	if err != nil {
 		return nil, err
 	}
-	params.Cmds = string(dataCmds)
-	params.Sign = Hmac(secret, params.Cmds)
-	dataParams, err := json.Marshal(params)
+	return &pairListResult, nil
+}
+
+//GetTicker Get Market Ticker Of A Pair
+func (bs *BiboxService) GetTicker(pair string) (*TickerResult, error) {
+	var tickerResult TickerResult
+	err := bs.sendOne(pathMdata, marketCMD(cmdTicker, pair, 0), &tickerResult, "get ticker")
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(dataParams))
+	return &tickerResult, nil
+}
+
+//GetMarketAll Get 24h Summary Of All Pairs
+func (bs *BiboxService) GetMarketAll() (*MarketAllResult, error) {
+	var marketAllResult MarketAllResult
+	err := bs.sendOne(pathMdata, marketCMD(cmdMarketAll, "", 0), &marketAllResult, "get market all")
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Content-Type", "application/json")
+	return &marketAllResult, nil
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+//GetKline Get Klines Of A Pair By Period
+func (bs *BiboxService) GetKline(pair, period string, size int) (*KlineResult, error) {
+	cmd := marketCMD(cmdKline, pair, size)
+	cmd.Body["period"] = period
+	var klineResult KlineResult
+	err := bs.sendOne(pathMdata, cmd, &klineResult, "get kline")
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
+	return &klineResult, nil
+}
+
+//GetDeals Get Recent Deals Of A Pair
+func (bs *BiboxService) GetDeals(pair string, size int) (*DealsResult, error) {
+	var dealsResult DealsResult
+	err := bs.sendOne(pathMdata, marketCMD(cmdDeals, pair, size), &dealsResult, "get deals")
 	if err != nil {
 		return nil, err
 	}
-	var results Results
-	var tradeResult TradeResult
-	err = json.Unmarshal(body, &results)
-	if err != nil {
+	return &dealsResult, nil
+}
+
+//Trade Trade in Bibox
+func (bs *BiboxService) Trade(tradeBody *TradeBody) (*TradeResult, error) {
+	if err := tradeBody.Validate(); err != nil {
 		return nil, err
 	}
-	if results.Error != nil {
-		return nil, errors.New(results.Error.Msg)
-	}
-	if len(results.Result) != 1 {
-		return nil, errors.New("get trade result length invalid")
-	}
-	err = json.Unmarshal(results.Result[0], &tradeResult)
+	cmd := tradeCMD(tradeBody)
+	cmd.Index = 1
+	var tradeResult TradeResult
+	err := bs.sendOne(pathOrderPending, cmd, &tradeResult, "get trade")
 	if err != nil {
 		return nil, err
 	}
@@ -274,61 +216,23 @@ func (bs *BiboxService) Trade(tradeBody *TradeBody) (*TradeResult, error) {
 
 //BatchTrade Batch Trade
 func (bs *BiboxService) BatchTrade(trades []*TradeBody) ([]*TradeResult, error) {
-	url := bs.URL + "v1/orderpending"
-	secret := bs.SecretKey
-	params := new(Params)
-	params.APIKey = bs.APIKey
 	cmds := make([]*CMD, 0)
 	for index, tradeBody := range trades {
-		cmd := new(CMD)
-		cmd.Cmd = "orderpending/trade"
+		if err := tradeBody.Validate(); err != nil {
+			return nil, fmt.Errorf("trade %d: %v", index, err)
+		}
+		cmd := tradeCMD(tradeBody)
 		cmd.Index = index + 1
-		cmd.Body = make(map[string]interface{})
-		cmd.Body["pair"] = tradeBody.Pair
-		cmd.Body["account_type"] = tradeBody.AccountType
-		cmd.Body["order_type"] = tradeBody.OrderType
-		cmd.Body["order_side"] = tradeBody.OrderSide
-		cmd.Body["pay_bix"] = tradeBody.PayBix
-		cmd.Body["price"] = tradeBody.Price
-		cmd.Body["amount"] = tradeBody.Amount
-		cmd.Body["money"] = tradeBody.Money
 		cmds = append(cmds, cmd)
 	}
-	dataCmds, err := json.Marshal(cmds)
-	if err != nil {
-		return nil, err
-	}
-	params.Cmds = string(dataCmds)
-	params.Sign = Hmac(secret, params.Cmds)
-	dataParams, err := json.Marshal(params)
-	if err != nil {
-		return nil, err
-	}
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(dataParams))
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	var results Results
-	var tradeResults []*TradeResult
-	err = json.Unmarshal(body, &results)
+	results, err := bs.send(pathOrderPending, cmds)
 	if err != nil {
 		return nil, err
 	}
 	if len(results.Result) != len(trades) {
 		return nil, errors.New("get trade result length invalid")
 	}
+	var tradeResults []*TradeResult
 	for _, result := range results.Result {
 		var oneResult TradeResult
 		err = json.Unmarshal(result, &oneResult)
@@ -345,56 +249,10 @@ func (bs *BiboxService) BatchTrade(trades []*TradeBody) ([]*TradeResult, error)
 
 //CancelTrade Cancel Pending Trade
 func (bs *BiboxService) CancelTrade(id uint64) (*CancelTradeResult, error) {
-	url := bs.URL + "v1/orderpending"
-	secret := bs.SecretKey
-	params := new(Params)
-	params.APIKey = bs.APIKey
-	cmd := new(CMD)
-	cmd.Cmd = "orderpending/cancelTrade"
+	cmd := cancelTradeCMD(id)
 	cmd.Index = 1
-	cmd.Body = make(map[string]interface{})
-	cmd.Body["orders_id"] = id
-	cmds := make([]*CMD, 0)
-	cmds = append(cmds, cmd)
-	dataCmds, err := json.Marshal(cmds)
-	if err != nil {
-		return nil, err
-	}
-	params.Cmds = string(dataCmds)
-	params.Sign = Hmac(secret, params.Cmds)
-	dataParams, err := json.Marshal(params)
-	if err != nil {
-		return nil, err
-	}
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(dataParams))
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	var results Results
 	var cancelResult CancelTradeResult
-	err = json.Unmarshal(body, &results)
-	if err != nil {
-		return nil, err
-	}
-	if results.Error != nil {
-		return nil, errors.New(results.Error.Msg)
-	}
-	if len(results.Result) != 1 {
-		return nil, errors.New("get cancel trade result length invalid")
-	}
-	err = json.Unmarshal(results.Result[0], &cancelResult)
+	err := bs.sendOne(pathOrderPending, cmd, &cancelResult, "get cancel trade")
 	if err != nil {
 		return nil, err
 	}
@@ -403,47 +261,13 @@ func (bs *BiboxService) CancelTrade(id uint64) (*CancelTradeResult, error) {
 
 //BatchCancelTrade Batch Cancel Pending Trade
 func (bs *BiboxService) BatchCancelTrade(ids []uint64) ([]*CancelTradeResult, error) {
-	url := bs.URL + "v1/orderpending"
-	secret := bs.SecretKey
-	params := new(Params)
 	cmds := make([]*CMD, 0)
-	params.APIKey = bs.APIKey
 	for index, id := range ids {
-		cmd := new(CMD)
-		cmd.Cmd = "orderpending/cancelTrade"
+		cmd := cancelTradeCMD(id)
 		cmd.Index = index + 1
-		cmd.Body = make(map[string]interface{})
-		cmd.Body["orders_id"] = id
 		cmds = append(cmds, cmd)
 	}
-	dataCmds, err := json.Marshal(cmds)
-	if err != nil {
-		return nil, err
-	}
-	params.Cmds = string(dataCmds)
-	params.Sign = Hmac(secret, params.Cmds)
-	dataParams, err := json.Marshal(params)
-	if err != nil {
-		return nil, err
-	}
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(dataParams))
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	var results Results
-	err = json.Unmarshal(body, &results)
+	results, err := bs.send(pathOrderPending, cmds)
 	if err != nil {
 		return nil, err
 	}
@@ -467,61 +291,26 @@ func (bs *BiboxService) BatchCancelTrade(ids []uint64) ([]*CancelTradeResult, er
 
 //CurrentPending Get Current Pending Orders
 func (bs *BiboxService) CurrentPending(pendingBody *PendingBody) (*PendingResult, error) {
-	url := bs.URL + "v1/orderpending"
-	secret := bs.SecretKey
-	params := new(Params)
-	params.APIKey = bs.APIKey
-	cmd := new(CMD)
-	cmd.Cmd = "orderpending/orderPendingList"
-	cmd.Index = 1
-	cmd.Body = make(map[string]interface{})
-	if pendingBody.Pair != "" {
-		cmd.Body["pair"] = pendingBody.Pair
-	}
-	if pendingBody.AccountType != -1 {
-		cmd.Body["account_type"] = pendingBody.AccountType
-	}
-	cmd.Body["page"] = pendingBody.Page
-	cmd.Body["size"] = pendingBody.Size
-	if pendingBody.CoinSymbol != "" {
-		cmd.Body["coin_symbol"] = pendingBody.CoinSymbol
-	}
-	if pendingBody.CurrencySymbol != "" {
-		cmd.Body["currency_symbol"] = pendingBody.CurrencySymbol
-	}
-	if pendingBody.OrderSide != 0 {
-		cmd.Body["order_side"] = pendingBody.OrderSide
-	}
-	cmds := make([]*CMD, 0)
-	cmds = append(cmds, cmd)
-	dataCmds, err := json.Marshal(cmds)
-	if err != nil {
-		return nil, err
-	}
-	params.Cmds = string(dataCmds)
-	params.Sign = Hmac(secret, params.Cmds)
-	dataParams, err := json.Marshal(params)
-	if err != nil {
+	if err := pendingBody.Validate(); err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(dataParams))
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
+	cmd := pendingCMD(cmdPendingList, pendingBody)
+	cmd.Index = 1
+	return bs.pending(cmd)
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
+//HistoryPending Get History Pending Orders
+func (bs *BiboxService) HistoryPending(pendingBody *PendingBody) (*PendingResult, error) {
+	if err := pendingBody.Validate(); err != nil {
 		return nil, err
 	}
-	var results Results
-	err = json.Unmarshal(body, &results)
+	cmd := pendingCMD(cmdPendingHistoryList, pendingBody)
+	cmd.Index = 1
+	return bs.pending(cmd)
+}
+
+func (bs *BiboxService) pending(cmd *CMD) (*PendingResult, error) {
+	results, err := bs.send(pathOrderPending, []*CMD{cmd})
 	if err != nil {
 		return nil, err
 	}
@@ -539,79 +328,142 @@ func (bs *BiboxService) CurrentPending(pendingBody *PendingBody) (*PendingResult
 	return &pendingResult, nil
 }
 
-//HistoryPending Get History Pending Orders
-func (bs *BiboxService) HistoryPending(pendingBody *PendingBody) (*PendingResult, error) {
-	url := bs.URL + "v1/orderpending"
-	secret := bs.SecretKey
-	params := new(Params)
-	params.APIKey = bs.APIKey
-	cmd := new(CMD)
-	cmd.Cmd = "orderpending/pendingHistoryList"
-	cmd.Index = 1
-	cmd.Body = make(map[string]interface{})
-	if pendingBody.Pair != "" {
-		cmd.Body["pair"] = pendingBody.Pair
-	}
-	if pendingBody.AccountType != -1 {
-		cmd.Body["account_type"] = pendingBody.AccountType
+//GetOrder Get Order Detail By ID
+func (bs *BiboxService) GetOrder(id uint64, accountType AccountType) (*OrderDetailResult, error) {
+	if !accountType.Valid() {
+		return nil, fmt.Errorf("invalid account type %v", accountType)
 	}
-	cmd.Body["page"] = pendingBody.Page
-	cmd.Body["size"] = pendingBody.Size
-	if pendingBody.CoinSymbol != "" {
-		cmd.Body["coin_symbol"] = pendingBody.CoinSymbol
+	cmd := orderCMD(cmdOrder, id, accountType)
+	cmd.Index = 1
+	var orderResult OrderDetailResult
+	err := bs.sendOne(pathOrderPending, cmd, &orderResult, "get order")
+	if err != nil {
+		return nil, err
 	}
-	if pendingBody.CurrencySymbol != "" {
-		cmd.Body["currency_symbol"] = pendingBody.CurrencySymbol
+	return &orderResult, nil
+}
+
+//GetOrderFills Get Fills Of An Order With Fee And Fee Coin
+func (bs *BiboxService) GetOrderFills(id uint64, accountType AccountType) (*OrderFillsResult, error) {
+	if !accountType.Valid() {
+		return nil, fmt.Errorf("invalid account type %v", accountType)
 	}
-	if pendingBody.OrderSide != 0 {
-		cmd.Body["order_side"] = pendingBody.OrderSide
+	cmd := orderCMD(cmdOrderDetail, id, accountType)
+	cmd.Index = 1
+	var fillsResult OrderFillsResult
+	err := bs.sendOne(pathOrderPending, cmd, &fillsResult, "get order fills")
+	if err != nil {
+		return nil, err
 	}
-	if pendingBody.HideCancel != -1 {
-		cmd.Body["hide_cancel"] = pendingBody.HideCancel
+	return &fillsResult, nil
+}
+
+//FillHistory Get Fill History, HideCancel Is Ignored
+func (bs *BiboxService) FillHistory(fillBody *PendingBody) (*FillHistoryResult, error) {
+	if err := fillBody.Validate(); err != nil {
+		return nil, err
 	}
-	cmds := make([]*CMD, 0)
-	cmds = append(cmds, cmd)
-	dataCmds, err := json.Marshal(cmds)
+	cmd := pendingCMD(cmdOrderHistoryList, fillBody)
+	cmd.Index = 1
+	var historyResult FillHistoryResult
+	err := bs.sendOne(pathOrderPending, cmd, &historyResult, "fill history")
 	if err != nil {
 		return nil, err
 	}
-	params.Cmds = string(dataCmds)
-	params.Sign = Hmac(secret, params.Cmds)
-	dataParams, err := json.Marshal(params)
+	return &historyResult, nil
+}
+
+//IsOrderFinished Order Is Filled Or Cancelled
+func (bs *BiboxService) IsOrderFinished(id uint64, accountType AccountType) (bool, error) {
+	order, err := bs.GetOrder(id, accountType)
+	if err != nil {
+		return false, err
+	}
+	return order.Result.Status.Finished(), nil
+}
+
+//GetDepositAddress Get Deposit Address Of A Coin
+func (bs *BiboxService) GetDepositAddress(coinSymbol string) (string, error) {
+	body := make(map[string]interface{})
+	body["coin_symbol"] = coinSymbol
+	var addressResult DepositAddressResult
+	err := bs.sendOne(pathTransfer, transferCMD(cmdTransferIn, body), &addressResult, "get deposit address")
 	if err != nil {
+		return "", err
+	}
+	return addressResult.Result, nil
+}
+
+//DepositHistory Get Deposit History
+func (bs *BiboxService) DepositHistory(listBody *TransferListBody) (*TransferListResult, error) {
+	if err := listBody.Validate(); err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(dataParams))
+	var listResult TransferListResult
+	err := bs.sendOne(pathTransfer, transferListCMD(cmdTransferInList, listBody), &listResult, "deposit history")
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Content-Type", "application/json")
+	return &listResult, nil
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
+//Withdraw Request A Withdrawal, Returns Withdrawal ID
+func (bs *BiboxService) Withdraw(withdrawBody *WithdrawBody) (uint64, error) {
+	if err := withdrawBody.Validate(); err != nil {
+		return 0, err
+	}
+	body := make(map[string]interface{})
+	body["trade_pwd"] = withdrawBody.TradePwd
+	body["coin_symbol"] = withdrawBody.CoinSymbol
+	body["amount"] = withdrawBody.Amount
+	body["addr"] = withdrawBody.Addr
+	body["addr_remark"] = withdrawBody.AddrRemark
+	if withdrawBody.Memo != "" {
+		body["memo"] = withdrawBody.Memo
+	}
+	var withdrawResult WithdrawResult
+	err := bs.sendOne(pathTransfer, transferCMD(cmdTransferOut, body), &withdrawResult, "withdraw")
+	if err != nil {
+		return 0, err
+	}
+	return withdrawResult.Result, nil
+}
+
+//WithdrawHistory Get Withdrawal History
+func (bs *BiboxService) WithdrawHistory(listBody *TransferListBody) (*TransferListResult, error) {
+	if err := listBody.Validate(); err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
+	var listResult TransferListResult
+	err := bs.sendOne(pathTransfer, transferListCMD(cmdTransferOutList, listBody), &listResult, "withdraw history")
 	if err != nil {
 		return nil, err
 	}
-	var results Results
-	err = json.Unmarshal(body, &results)
+	return &listResult, nil
+}
+
+//CancelWithdraw Cancel A Pending Withdrawal
+func (bs *BiboxService) CancelWithdraw(id uint64) (*CancelWithdrawResult, error) {
+	body := make(map[string]interface{})
+	body["id"] = id
+	var cancelResult CancelWithdrawResult
+	err := bs.sendOne(pathTransfer, transferCMD(cmdCancelTransferOut, body), &cancelResult, "cancel withdraw")
 	if err != nil {
 		return nil, err
 	}
-	if results.Error != nil {
-		return nil, errors.New(results.Error.Msg)
-	}
-	if len(results.Result) != 1 {
-		return nil, errors.New("pending result length invalid")
+	return &cancelResult, nil
+}
+
+//GetCoinConfig Get Min Withdraw And Fees, Empty coinSymbol For All Coins
+func (bs *BiboxService) GetCoinConfig(coinSymbol string) (*CoinConfigResult, error) {
+	body := make(map[string]interface{})
+	if coinSymbol != "" {
+		body["coin_symbol"] = coinSymbol
 	}
-	var pendingResult PendingResult
-	err = json.Unmarshal(results.Result[0], &pendingResult)
+	var configResult CoinConfigResult
+	err := bs.sendOne(pathTransfer, transferCMD(cmdCoinConfig, body), &configResult, "get coin config")
 	if err != nil {
-		return nil, errors.New("parse pending result error")
+		return nil, err
 	}
-	return &pendingResult, nil
+	return &configResult, nil
 }
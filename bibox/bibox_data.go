@@ -52,6 +52,88 @@ type DepthResult struct {
 	CMD string `json:"cmd"`
 }
 
+//PairListResult Trading Pair List Result
+type PairListResult struct {
+	Result []struct {
+		ID   int    `json:"id"`
+		Pair string `json:"pair"`
+	} `json:"result"`
+	CMD string `json:"cmd"`
+}
+
+//TickerResult Market Ticker Result
+type TickerResult struct {
+	Result struct {
+		Pair       string `json:"pair"`
+		Last       string `json:"last"`
+		LastUSD    string `json:"last_usd"`
+		LastCNY    string `json:"last_cny"`
+		High       string `json:"high"`
+		Low        string `json:"low"`
+		Buy        string `json:"buy"`
+		BuyAmount  string `json:"buy_amount"`
+		Sell       string `json:"sell"`
+		SellAmount string `json:"sell_amount"`
+		Vol        string `json:"vol"`
+		Percent    string `json:"percent"`
+		Timestamp  uint64 `json:"timestamp"`
+	} `json:"result"`
+	CMD string `json:"cmd"`
+}
+
+//MarketAllResult All Market Summary Result
+type MarketAllResult struct {
+	Result []MarketSummary `json:"result"`
+	CMD    string          `json:"cmd"`
+}
+
+//MarketSummary 24h Summary Of One Pair
+type MarketSummary struct {
+	ID             int    `json:"id"`
+	CoinSymbol     string `json:"coin_symbol"`
+	CurrencySymbol string `json:"currency_symbol"`
+	Last           string `json:"last"`
+	High           string `json:"high"`
+	Low            string `json:"low"`
+	Change         string `json:"change"`
+	Percent        string `json:"percent"`
+	Vol24H         string `json:"vol24H"`
+	Amount         string `json:"amount"`
+	LastCNY        string `json:"last_cny"`
+	LastUSD        string `json:"last_usd"`
+}
+
+//KlineResult Kline Result
+type KlineResult struct {
+	Result []Kline `json:"result"`
+	CMD    string  `json:"cmd"`
+}
+
+//Kline One Bar, Time In Milliseconds
+type Kline struct {
+	Time  uint64 `json:"time"`
+	Open  string `json:"open"`
+	High  string `json:"high"`
+	Low   string `json:"low"`
+	Close string `json:"close"`
+	Vol   string `json:"vol"`
+}
+
+//DealsResult Recent Deals Result
+type DealsResult struct {
+	Result []Deal `json:"result"`
+	CMD    string `json:"cmd"`
+}
+
+//Deal One Market Deal, Side 1 Buy 2 Sell, Time In Milliseconds
+type Deal struct {
+	Pair   string `json:"pair"`
+	Price  string `json:"price"`
+	Amount string `json:"amount"`
+	Time   uint64 `json:"time"`
+	Side   int    `json:"side"`
+}
+
 //Order Order
 type Order struct {
 	Price  string `json:"price"`
@@ -86,26 +168,26 @@ type TradeResult struct {
 
 //TradeBody Trade Body
 type TradeBody struct {
-	Pair        string  `json:"pair"`
-	AccountType int     `json:"account_type"`
-	OrderType   int     `json:"order_type"`
-	OrderSide   int     `json:"order_side"`
-	PayBix      int     `json:"pay_bix"`
-	Price       float64 `json:"price"`
-	Amount      float64 `json:"amount"`
-	Money       float64 `json:"money"`
+	Pair        string      `json:"pair"`
+	AccountType AccountType `json:"account_type"`
+	OrderType   OrderType   `json:"order_type"`
+	OrderSide   OrderSide   `json:"order_side"`
+	PayBix      PayBix      `json:"pay_bix"`
+	Price       float64     `json:"price"`
+	Amount      float64     `json:"amount"`
+	Money       float64     `json:"money"`
 }
 
-//PendingBody Current Pending Body
+//PendingBody Current Pending Body, nil Filters Are Not Sent
 type PendingBody struct {
-	Pair           string `json:"pair"`
-	AccountType    int    `json:"account_type"`
-	Page           int    `json:"page"`
-	Size           int    `json:"size"`
-	CoinSymbol     string `json:"coin_symbol"`
-	CurrencySymbol string `json:"currency_symbol"`
-	OrderSide      int    `json:"order_side"`
-	HideCancel     int    `json:"hide_cancel"`
+	Pair           string       `json:"pair"`
+	AccountType    *AccountType `json:"account_type"`
+	Page           int          `json:"page"`
+	Size           int          `json:"size"`
+	CoinSymbol     string       `json:"coin_symbol"`
+	CurrencySymbol string       `json:"currency_symbol"`
+	OrderSide      *OrderSide   `json:"order_side"`
+	HideCancel     *HideCancel  `json:"hide_cancel"` //Only For History
 }
 
 //PendingResult PendingResult
@@ -120,18 +202,143 @@ type PendingResult struct {
 
 //PendingItem PendingItem
 type PendingItem struct {
-	ID             int    `json:"id"`
-	CreatedAt      uint64 `json:"createdAt"`
-	AccountType    int    `json:"account_type"`
+	ID             int         `json:"id"`
+	CreatedAt      uint64      `json:"createdAt"`
+	AccountType    AccountType `json:"account_type"`
+	CoinSymbol     string      `json:"coin_symbol"`
+	CurrencySymbol string      `json:"currency_symbol"`
+	OrderSide      OrderSide   `json:"order_side"`
+	OrderType      OrderType   `json:"order_type"`
+	Price          string      `json:"price"`
+	Amount         string      `json:"amount"`
+	Money          string      `json:"money"`
+	DealAmount     string      `json:"deal_amount"`
+	DealPercent    string      `json:"deal_percent"`
+	UnExcecuted    string      `json:"unexecuted"`
+	Status         OrderStatus `json:"status"`
+}
+
+//OrderDetailResult Single Order Result
+type OrderDetailResult struct {
+	Result OrderDetail `json:"result"`
+	CMD    string      `json:"cmd"`
+}
+
+//OrderDetail Order With Its Deal Summary
+type OrderDetail struct {
+	PendingItem
+	DealPrice string `json:"deal_price"`
+	DealMoney string `json:"deal_money"`
+}
+
+//Fill One Deal Of An Order, Fee Is Charged In FeeSymbol
+type Fill struct {
+	ID             uint64      `json:"id"`
+	RelayID        uint64      `json:"relay_id"` //Order ID
+	CreatedAt      uint64      `json:"createdAt"`
+	AccountType    AccountType `json:"account_type"`
+	CoinSymbol     string      `json:"coin_symbol"`
+	CurrencySymbol string      `json:"currency_symbol"`
+	OrderSide      OrderSide   `json:"order_side"`
+	OrderType      OrderType   `json:"order_type"`
+	Price          string      `json:"price"`
+	Amount         string      `json:"amount"`
+	Money          string      `json:"money"`
+	Fee            string      `json:"fee"`
+	FeeSymbol      string      `json:"fee_symbol"`
+	PayBix         PayBix      `json:"pay_bix"`
+}
+
+//OrderFillsResult Fills Of A Single Order
+type OrderFillsResult struct {
+	Result struct {
+		Sum       string `json:"sum"`
+		OrderList []Fill `json:"orderList"`
+	} `json:"result"`
+	CMD string `json:"cmd"`
+}
+
+//FillHistoryResult Paged Fill History
+type FillHistoryResult struct {
+	Result struct {
+		Count int    `json:"count"`
+		Page  int    `json:"page"`
+		Items []Fill `json:"items"`
+	} `json:"result"`
+	CMD string `json:"cmd"`
+}
+
+//DepositAddressResult Deposit Address Result
+type DepositAddressResult struct {
+	Result string `json:"result"`
+	CMD    string `json:"cmd"`
+}
+
+//TransferListBody Deposit Or Withdrawal History Body
+type TransferListBody struct {
+	Page       int    `json:"page"`
+	Size       int    `json:"size"`
+	CoinSymbol string `json:"coin_symbol"` //Empty For All Coins
+}
+
+//TransferRecord One Deposit Or Withdrawal
+type TransferRecord struct {
+	ID           uint64 `json:"id"`
+	CoinSymbol   string `json:"coin_symbol"`
+	ToAddress    string `json:"to_address"`
+	Amount       string `json:"amount"`
+	Fee          string `json:"fee"`
+	ConfirmCount int    `json:"confirmCount"`
+	Memo         string `json:"memo"`
+	CreatedAt    uint64 `json:"createdAt"`
+	Status       int    `json:"status"`
+}
+
+//TransferListResult Deposit Or Withdrawal History Result
+type TransferListResult struct {
+	Result struct {
+		Count int              `json:"count"`
+		Page  int              `json:"page"`
+		Items []TransferRecord `json:"items"`
+	} `json:"result"`
+	CMD string `json:"cmd"`
+}
+
+//WithdrawBody Withdrawal Request Body
+type WithdrawBody struct {
+	TradePwd   string  `json:"trade_pwd"`
+	CoinSymbol string  `json:"coin_symbol"`
+	Amount     float64 `json:"amount"`
+	Addr       string  `json:"addr"`
+	AddrRemark string  `json:"addr_remark"`
+	Memo       string  `json:"memo"` //For Coins Like EOS
+}
+
+//WithdrawResult Withdrawal ID
+type WithdrawResult struct {
+	Result uint64 `json:"result"`
+	CMD    string `json:"cmd"`
+}
+
+//CancelWithdrawResult Cancel Withdrawal Result
+type CancelWithdrawResult struct {
+	Result string `json:"result"`
+	CMD    string `json:"cmd"`
+}
+
+//CoinConfigResult Coin Config Result
+type CoinConfigResult struct {
+	Result []CoinConfig `json:"result"`
+	CMD    string       `json:"cmd"`
+}
+
+//CoinConfig Deposit And Withdrawal Config Of A Coin
+type CoinConfig struct {
 	CoinSymbol     string `json:"coin_symbol"`
-	CurrencySymbol string `json:"currency_symbol"`
-	OrderSide      int    `json:"order_side"`
-	OrderType      int    `json:"order_type"`
-	Price          string `json:"price"`
-	Amount         string `json:"amount"`
-	Money          string `json:"money"`
-	DealAmount     string `json:"deal_amount"`
-	DealPercent    string `json:"deal_percent"`
-	UnExcecuted    string `json:"unexecuted"`
-	Status         int    `json:"status"`
+	IsActive       int    `json:"is_active"`
+	EnableDeposit  int    `json:"enable_deposit"`
+	EnableWithdraw int    `json:"enable_withdraw"`
+	WithdrawFee    string `json:"withdraw_fee"`
+	WithdrawMin    string `json:"withdraw_min"`
+	DepositAvg     int    `json:"deposit_avg"`
 }
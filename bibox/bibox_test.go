@@ -362,3 +362,92 @@ func TestSign(t *testing.T) {
 	data := Hmac(secret, cmds)
 	assert.Equal(t, data, "", "md5 hmac not equal")
 }
+
+func TestPairList(t *testing.T) {
+	url := "https://api.bibox.com/"
+	s, err := NewBiboxService(url, "", "")
+	if err != nil {
+		t.Error(err)
+	}
+	result, err := s.GetPairList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Println(len(result.Result))
+}
+
+func TestKline(t *testing.T) {
+	url := "https://api.bibox.com/"
+	s, err := NewBiboxService(url, "", "")
+	if err != nil {
+		t.Error(err)
+	}
+	result, err := s.GetKline("BIX_BTC", Period1Hour, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range result.Result {
+		fmt.Println(k.Time, k.Open, k.Close)
+	}
+}
+
+func TestDeals(t *testing.T) {
+	url := "https://api.bibox.com/"
+	s, err := NewBiboxService(url, "", "")
+	if err != nil {
+		t.Error(err)
+	}
+	result, err := s.GetDeals("BIX_BTC", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Println(result.Result)
+}
+
+func TestGetOrder(t *testing.T) {
+	url := "https://api.bibox.com/"
+	s, err := NewBiboxService(url, "", "")
+	if err != nil {
+		t.Error(err)
+	}
+	result, err := s.GetOrder(612216386, AccountTypeCommon)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Println(result.Result.Status, result.Result.DealPrice)
+	fills, err := s.GetOrderFills(612216386, AccountTypeCommon)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, fill := range fills.Result.OrderList {
+		fmt.Println(fill.Price, fill.Amount, fill.Fee, fill.FeeSymbol)
+	}
+}
+
+func TestCoinConfig(t *testing.T) {
+	url := "https://api.bibox.com/"
+	s, err := NewBiboxService(url, "", "")
+	if err != nil {
+		t.Error(err)
+	}
+	result, err := s.GetCoinConfig("BIX")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range result.Result {
+		fmt.Println(c.CoinSymbol, c.WithdrawMin, c.WithdrawFee)
+	}
+}
+
+func TestDepositHistory(t *testing.T) {
+	url := "https://api.bibox.com/"
+	s, err := NewBiboxService(url, "", "")
+	if err != nil {
+		t.Error(err)
+	}
+	result, err := s.DepositHistory(&TransferListBody{Page: 1, Size: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Println(result.Result.Items)
+}
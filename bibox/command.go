@@ -0,0 +1,141 @@
+package bibox
+
+//Endpoint Paths Relative To BiboxService.URL
+const (
+	pathMdata        = "v1/mdata"
+	pathTransfer     = "v1/transfer"
+	pathOrderPending = "v1/orderpending"
+)
+
+//Command Names
+const (
+	cmdDepth              = "api/depth"
+	cmdPairList           = "api/pairList"
+	cmdTicker             = "api/ticker"
+	cmdMarketAll          = "api/marketAll"
+	cmdKline              = "api/kline"
+	cmdDeals              = "api/deals"
+	cmdAssets             = "transfer/assets"
+	cmdTransferIn         = "transfer/transferIn"
+	cmdTransferInList     = "transfer/transferInList"
+	cmdTransferOut        = "transfer/transferOut"
+	cmdTransferOutList    = "transfer/transferOutList"
+	cmdCancelTransferOut  = "transfer/cancelTransferOut"
+	cmdCoinConfig         = "transfer/coinConfig"
+	cmdTrade              = "orderpending/trade"
+	cmdCancelTrade        = "orderpending/cancelTrade"
+	cmdPendingList        = "orderpending/orderPendingList"
+	cmdPendingHistoryList = "orderpending/pendingHistoryList"
+	cmdOrder              = "orderpending/order"
+	cmdOrderDetail        = "orderpending/orderDetail"
+	cmdOrderHistoryList   = "orderpending/orderHistoryList"
+)
+
+func assetsCMD() *CMD {
+	cmd := new(CMD)
+	cmd.Cmd = cmdAssets
+	cmd.Body = make(map[string]interface{})
+	cmd.Body["select"] = 1
+	return cmd
+}
+
+func depthCMD(pair string, size int) *CMD {
+	cmd := new(CMD)
+	cmd.Cmd = cmdDepth
+	cmd.Body = make(map[string]interface{})
+	cmd.Body["pair"] = pair
+	cmd.Body["size"] = size
+	return cmd
+}
+
+//marketCMD Build A Market Data Command, Empty pair And Zero size Are Omitted
+func marketCMD(name, pair string, size int) *CMD {
+	cmd := new(CMD)
+	cmd.Cmd = name
+	cmd.Body = make(map[string]interface{})
+	if pair != "" {
+		cmd.Body["pair"] = pair
+	}
+	if size > 0 {
+		cmd.Body["size"] = size
+	}
+	return cmd
+}
+
+func tradeCMD(tradeBody *TradeBody) *CMD {
+	cmd := new(CMD)
+	cmd.Cmd = cmdTrade
+	cmd.Body = make(map[string]interface{})
+	cmd.Body["pair"] = tradeBody.Pair
+	cmd.Body["account_type"] = tradeBody.AccountType
+	cmd.Body["order_type"] = tradeBody.OrderType
+	cmd.Body["order_side"] = tradeBody.OrderSide
+	cmd.Body["pay_bix"] = tradeBody.PayBix
+	cmd.Body["price"] = tradeBody.Price
+	cmd.Body["amount"] = tradeBody.Amount
+	cmd.Body["money"] = tradeBody.Money
+	return cmd
+}
+
+func cancelTradeCMD(id uint64) *CMD {
+	cmd := new(CMD)
+	cmd.Cmd = cmdCancelTrade
+	cmd.Body = make(map[string]interface{})
+	cmd.Body["orders_id"] = id
+	return cmd
+}
+
+func orderCMD(name string, id uint64, accountType AccountType) *CMD {
+	cmd := new(CMD)
+	cmd.Cmd = name
+	cmd.Body = make(map[string]interface{})
+	cmd.Body["id"] = id
+	cmd.Body["account_type"] = accountType
+	return cmd
+}
+
+//pendingCMD Build Pending Or Fill List Command, hide_cancel Only Applies To Pending History
+func pendingCMD(name string, pendingBody *PendingBody) *CMD {
+	cmd := new(CMD)
+	cmd.Cmd = name
+	cmd.Body = make(map[string]interface{})
+	if pendingBody.Pair != "" {
+		cmd.Body["pair"] = pendingBody.Pair
+	}
+	if pendingBody.AccountType != nil {
+		cmd.Body["account_type"] = *pendingBody.AccountType
+	}
+	cmd.Body["page"] = pendingBody.Page
+	cmd.Body["size"] = pendingBody.Size
+	if pendingBody.CoinSymbol != "" {
+		cmd.Body["coin_symbol"] = pendingBody.CoinSymbol
+	}
+	if pendingBody.CurrencySymbol != "" {
+		cmd.Body["currency_symbol"] = pendingBody.CurrencySymbol
+	}
+	if pendingBody.OrderSide != nil {
+		cmd.Body["order_side"] = *pendingBody.OrderSide
+	}
+	if name == cmdPendingHistoryList && pendingBody.HideCancel != nil {
+		cmd.Body["hide_cancel"] = *pendingBody.HideCancel
+	}
+	return cmd
+}
+
+//transferCMD Build A Transfer Command With Given Body
+func transferCMD(name string, body map[string]interface{}) *CMD {
+	cmd := new(CMD)
+	cmd.Cmd = name
+	cmd.Body = body
+	return cmd
+}
+
+func transferListCMD(name string, listBody *TransferListBody) *CMD {
+	body := make(map[string]interface{})
+	body["page"] = listBody.Page
+	body["size"] = listBody.Size
+	if listBody.CoinSymbol != "" {
+		body["coin_symbol"] = listBody.CoinSymbol
+	}
+	return transferCMD(name, body)
+}
@@ -0,0 +1,103 @@
+package bibox
+
+import (
+	"time"
+
+	"go-exchange/batch"
+)
+
+//DefaultPageInterval Default Min Interval Between Page Requests
+const DefaultPageInterval = 200 * time.Millisecond
+
+//PendingIterator Walk Pending Or History Orders Page By Page, Newest First.
+//Stops When A Page Is Short Or All count Items Are Read
+type PendingIterator struct {
+	batch.Pacer
+	page  int
+	size  int
+	read  int
+	fetch func(page, size int) (*PendingResult, error)
+	buf   []PendingItem
+	cur   PendingItem
+	done  bool
+	err   error
+}
+
+func newPendingIterator(pendingBody *PendingBody, fetch func(*PendingBody) (*PendingResult, error)) *PendingIterator {
+	body := *pendingBody
+	if body.Page < 1 {
+		body.Page = 1
+	}
+	if body.Size < 1 {
+		body.Size = 20
+	}
+	return &PendingIterator{
+		Pacer: batch.Pacer{Interval: DefaultPageInterval},
+		page:  body.Page,
+		size:  body.Size,
+		fetch: func(page, size int) (*PendingResult, error) {
+			body.Page, body.Size = page, size
+			return fetch(&body)
+		},
+	}
+}
+
+//NewCurrentPendingIterator Iterate CurrentPending From pendingBody.Page
+func (bs *BiboxService) NewCurrentPendingIterator(pendingBody *PendingBody) *PendingIterator {
+	return newPendingIterator(pendingBody, bs.CurrentPending)
+}
+
+//NewHistoryPendingIterator Iterate HistoryPending From pendingBody.Page
+func (bs *BiboxService) NewHistoryPendingIterator(pendingBody *PendingBody) *PendingIterator {
+	return newPendingIterator(pendingBody, bs.HistoryPending)
+}
+
+//Next Move To Next Order, false When No More Or Error
+func (it *PendingIterator) Next() bool {
+	for len(it.buf) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		it.Wait()
+		result, err := it.fetch(it.page, it.size)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		items := result.Result.Items
+		it.read += len(items)
+		it.page++
+		if len(items) < it.size || (result.Result.Count > 0 && it.read >= result.Result.Count) {
+			it.done = true
+		}
+		it.buf = items
+	}
+	it.cur, it.buf = it.buf[0], it.buf[1:]
+	return true
+}
+
+//Item Current Order
+func (it *PendingIterator) Item() PendingItem {
+	return it.cur
+}
+
+//Err Error During Iteration
+func (it *PendingIterator) Err() error {
+	return it.err
+}
+
+//Collect Read All Orders Created In [from, to]
+func (it *PendingIterator) Collect(from, to time.Time) ([]PendingItem, error) {
+	fromMs, toMs := uint64(from.UnixNano()/1e6), uint64(to.UnixNano()/1e6)
+	res := make([]PendingItem, 0)
+	for it.Next() {
+		item := it.Item()
+		if item.CreatedAt < fromMs {
+			break
+		}
+		if item.CreatedAt <= toMs {
+			res = append(res, item)
+		}
+	}
+	return res, it.Err()
+}
@@ -0,0 +1,55 @@
+package bibox
+
+import (
+	"testing"
+	"time"
+)
+
+//fakePending Serve total Items With CreatedAt Descending From total*1000
+func fakePending(total int, calls *int) func(*PendingBody) (*PendingResult, error) {
+	return func(pb *PendingBody) (*PendingResult, error) {
+		*calls++
+		res := new(PendingResult)
+		res.Result.Count = total
+		res.Result.Page = pb.Page
+		for i := (pb.Page - 1) * pb.Size; i < pb.Page*pb.Size && i < total; i++ {
+			res.Result.Items = append(res.Result.Items, PendingItem{ID: i, CreatedAt: uint64((total - i) * 1000)})
+		}
+		return res, nil
+	}
+}
+
+func TestPendingIterator(t *testing.T) {
+	calls := 0
+	it := newPendingIterator(&PendingBody{Size: 10}, fakePending(30, &calls))
+	it.Interval = 0
+	count := 0
+	for it.Next() {
+		if it.Item().ID != count {
+			t.Fatalf("item %d at %d", it.Item().ID, count)
+		}
+		count++
+	}
+	if it.Err() != nil {
+		t.Fatal(it.Err())
+	}
+	if count != 30 || calls != 3 {
+		t.Fatalf("count %d calls %d", count, calls)
+	}
+}
+
+func TestPendingIteratorCollect(t *testing.T) {
+	calls := 0
+	it := newPendingIterator(&PendingBody{Page: 1, Size: 4}, fakePending(30, &calls))
+	it.Interval = 0
+	res, err := it.Collect(time.Unix(20, 0), time.Unix(25, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 6 || res[0].CreatedAt != 25000 || res[5].CreatedAt != 20000 {
+		t.Fatal(res)
+	}
+	if calls != 3 {
+		t.Fatalf("collect should stop early, calls %d", calls)
+	}
+}
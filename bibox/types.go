@@ -0,0 +1,244 @@
+package bibox
+
+import (
+	"errors"
+	"fmt"
+)
+
+//AccountType Account Type
+type AccountType int
+
+//Account Types
+const (
+	AccountTypeCommon AccountType = 0 //Spot Account
+	AccountTypeCredit AccountType = 1 //Credit Account
+)
+
+func (t AccountType) String() string {
+	switch t {
+	case AccountTypeCommon:
+		return "common"
+	case AccountTypeCredit:
+		return "credit"
+	}
+	return fmt.Sprintf("AccountType(%d)", int(t))
+}
+
+//Valid Is A Known Account Type
+func (t AccountType) Valid() bool {
+	return t == AccountTypeCommon || t == AccountTypeCredit
+}
+
+//OrderType Order Type
+type OrderType int
+
+//Order Types
+const (
+	OrderTypeMarket OrderType = 1
+	OrderTypeLimit  OrderType = 2
+)
+
+func (t OrderType) String() string {
+	switch t {
+	case OrderTypeMarket:
+		return "market"
+	case OrderTypeLimit:
+		return "limit"
+	}
+	return fmt.Sprintf("OrderType(%d)", int(t))
+}
+
+//Valid Is A Known Order Type
+func (t OrderType) Valid() bool {
+	return t == OrderTypeMarket || t == OrderTypeLimit
+}
+
+//OrderSide Order Side
+type OrderSide int
+
+//Order Sides
+const (
+	OrderSideBuy  OrderSide = 1
+	OrderSideSell OrderSide = 2
+)
+
+func (s OrderSide) String() string {
+	switch s {
+	case OrderSideBuy:
+		return "buy"
+	case OrderSideSell:
+		return "sell"
+	}
+	return fmt.Sprintf("OrderSide(%d)", int(s))
+}
+
+//Valid Is A Known Order Side
+func (s OrderSide) Valid() bool {
+	return s == OrderSideBuy || s == OrderSideSell
+}
+
+//PayBix Whether Fees Are Paid In BIX
+type PayBix int
+
+//PayBix Values
+const (
+	PayBixNo  PayBix = 0
+	PayBixYes PayBix = 1
+)
+
+func (p PayBix) String() string {
+	switch p {
+	case PayBixNo:
+		return "no"
+	case PayBixYes:
+		return "yes"
+	}
+	return fmt.Sprintf("PayBix(%d)", int(p))
+}
+
+//Valid Is A Known PayBix Value
+func (p PayBix) Valid() bool {
+	return p == PayBixNo || p == PayBixYes
+}
+
+//HideCancel Whether History Hides Cancelled Orders
+type HideCancel int
+
+//HideCancel Values
+const (
+	HideCancelNo  HideCancel = 0
+	HideCancelYes HideCancel = 1
+)
+
+func (h HideCancel) String() string {
+	switch h {
+	case HideCancelNo:
+		return "no"
+	case HideCancelYes:
+		return "yes"
+	}
+	return fmt.Sprintf("HideCancel(%d)", int(h))
+}
+
+//Valid Is A Known HideCancel Value
+func (h HideCancel) Valid() bool {
+	return h == HideCancelNo || h == HideCancelYes
+}
+
+//OrderStatus Order Status
+type OrderStatus int
+
+//Order Statuses
+const (
+	OrderStatusPending         OrderStatus = 1 //Not Filled
+	OrderStatusPartialFilled   OrderStatus = 2
+	OrderStatusFilled          OrderStatus = 3
+	OrderStatusPartialCanceled OrderStatus = 4
+	OrderStatusCanceled        OrderStatus = 5
+	OrderStatusCanceling       OrderStatus = 6
+)
+
+func (s OrderStatus) String() string {
+	switch s {
+	case OrderStatusPending:
+		return "pending"
+	case OrderStatusPartialFilled:
+		return "partial_filled"
+	case OrderStatusFilled:
+		return "filled"
+	case OrderStatusPartialCanceled:
+		return "partial_canceled"
+	case OrderStatusCanceled:
+		return "canceled"
+	case OrderStatusCanceling:
+		return "canceling"
+	}
+	return fmt.Sprintf("OrderStatus(%d)", int(s))
+}
+
+//Valid Is A Known Order Status
+func (s OrderStatus) Valid() bool {
+	return s >= OrderStatusPending && s <= OrderStatusCanceling
+}
+
+//Finished Order Will Not Change Any More
+func (s OrderStatus) Finished() bool {
+	return s == OrderStatusFilled || s == OrderStatusPartialCanceled || s == OrderStatusCanceled
+}
+
+//Validate Check Trade Body Before It Is Signed And Sent
+func (tb *TradeBody) Validate() error {
+	if tb.Pair == "" {
+		return errors.New("trade pair is empty")
+	}
+	if !tb.AccountType.Valid() {
+		return fmt.Errorf("invalid account type %v", tb.AccountType)
+	}
+	if !tb.OrderType.Valid() {
+		return fmt.Errorf("invalid order type %v", tb.OrderType)
+	}
+	if !tb.OrderSide.Valid() {
+		return fmt.Errorf("invalid order side %v", tb.OrderSide)
+	}
+	if !tb.PayBix.Valid() {
+		return fmt.Errorf("invalid pay bix %v", tb.PayBix)
+	}
+	if tb.Price < 0 || tb.Amount < 0 || tb.Money < 0 {
+		return errors.New("trade price amount and money must not be negative")
+	}
+	if tb.OrderType == OrderTypeLimit && (tb.Price <= 0 || tb.Amount <= 0) {
+		return errors.New("limit order needs positive price and amount")
+	}
+	if tb.OrderType == OrderTypeMarket && tb.Amount <= 0 && tb.Money <= 0 {
+		return errors.New("market order needs positive amount or money")
+	}
+	return nil
+}
+
+//Validate Check Pending Body Before It Is Signed And Sent
+func (pb *PendingBody) Validate() error {
+	if pb.Page < 1 {
+		return fmt.Errorf("invalid page %d", pb.Page)
+	}
+	if pb.Size < 1 {
+		return fmt.Errorf("invalid size %d", pb.Size)
+	}
+	if pb.AccountType != nil && !pb.AccountType.Valid() {
+		return fmt.Errorf("invalid account type %v", *pb.AccountType)
+	}
+	if pb.OrderSide != nil && !pb.OrderSide.Valid() {
+		return fmt.Errorf("invalid order side %v", *pb.OrderSide)
+	}
+	if pb.HideCancel != nil && !pb.HideCancel.Valid() {
+		return fmt.Errorf("invalid hide cancel %v", *pb.HideCancel)
+	}
+	return nil
+}
+
+//Validate Check Transfer List Body Before It Is Signed And Sent
+func (lb *TransferListBody) Validate() error {
+	if lb.Page < 1 {
+		return fmt.Errorf("invalid page %d", lb.Page)
+	}
+	if lb.Size < 1 {
+		return fmt.Errorf("invalid size %d", lb.Size)
+	}
+	return nil
+}
+
+//Validate Check Withdraw Body Before It Is Signed And Sent
+func (wb *WithdrawBody) Validate() error {
+	if wb.TradePwd == "" {
+		return errors.New("withdraw trade password is empty")
+	}
+	if wb.CoinSymbol == "" {
+		return errors.New("withdraw coin symbol is empty")
+	}
+	if wb.Addr == "" {
+		return errors.New("withdraw address is empty")
+	}
+	if wb.Amount <= 0 {
+		return fmt.Errorf("invalid withdraw amount %v", wb.Amount)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package bibox
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTradeBodyValidate(t *testing.T) {
+	body := &TradeBody{
+		Pair:      "BIX_ETH",
+		OrderType: OrderTypeLimit,
+		OrderSide: OrderSideBuy,
+		Price:     0.0001,
+		Amount:    1,
+	}
+	if err := body.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	invalid := []TradeBody{
+		{OrderType: OrderTypeLimit, OrderSide: OrderSideBuy, Price: 1, Amount: 1},
+		{Pair: "BIX_ETH", OrderType: 0, OrderSide: OrderSideBuy, Price: 1, Amount: 1},
+		{Pair: "BIX_ETH", OrderType: OrderTypeLimit, OrderSide: 0, Price: 1, Amount: 1},
+		{Pair: "BIX_ETH", AccountType: 2, OrderType: OrderTypeLimit, OrderSide: OrderSideBuy, Price: 1, Amount: 1},
+		{Pair: "BIX_ETH", OrderType: OrderTypeLimit, OrderSide: OrderSideBuy, PayBix: 2, Price: 1, Amount: 1},
+		{Pair: "BIX_ETH", OrderType: OrderTypeLimit, OrderSide: OrderSideBuy, Amount: 1},
+		{Pair: "BIX_ETH", OrderType: OrderTypeMarket, OrderSide: OrderSideSell},
+		{Pair: "BIX_ETH", OrderType: OrderTypeMarket, OrderSide: OrderSideSell, Amount: -1, Money: 1},
+	}
+	for i, b := range invalid {
+		if err := b.Validate(); err == nil {
+			t.Errorf("case %d should be invalid", i)
+		}
+	}
+}
+
+func TestPendingBodyOptionalFilters(t *testing.T) {
+	body := &PendingBody{Page: 1, Size: 10}
+	if err := body.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	cmd := pendingCMD(cmdPendingHistoryList, body)
+	for _, key := range []string{"account_type", "order_side", "hide_cancel"} {
+		if _, ok := cmd.Body[key]; ok {
+			t.Errorf("unset %s should not be sent", key)
+		}
+	}
+
+	accountType := AccountTypeCommon
+	side := OrderSideSell
+	hide := HideCancelYes
+	body.AccountType, body.OrderSide, body.HideCancel = &accountType, &side, &hide
+	cmd = pendingCMD(cmdPendingHistoryList, body)
+	if cmd.Body["account_type"] != AccountTypeCommon || cmd.Body["order_side"] != OrderSideSell || cmd.Body["hide_cancel"] != HideCancelYes {
+		t.Fatal(cmd.Body)
+	}
+
+	bad := OrderSide(5)
+	body.OrderSide = &bad
+	if err := body.Validate(); err == nil {
+		t.Fatal("invalid side should fail")
+	}
+}
+
+func TestEnumString(t *testing.T) {
+	if OrderSideBuy.String() != "buy" || OrderTypeLimit.String() != "limit" || OrderStatusPartialCanceled.String() != "partial_canceled" {
+		t.Fatal("unexpected enum names")
+	}
+	if OrderSide(9).String() != "OrderSide(9)" {
+		t.Fatal(OrderSide(9).String())
+	}
+	if !OrderStatusCanceled.Finished() || OrderStatusPartialFilled.Finished() {
+		t.Fatal("finished statuses wrong")
+	}
+}
+
+func TestFillDecode(t *testing.T) {
+	data := `{"result":{"sum":"1","orderList":[{"id":1,"relay_id":612216386,"createdAt":1512756997000,"account_type":0,"coin_symbol":"BIX","currency_symbol":"ETH","order_side":2,"order_type":2,"price":"0.0001","amount":"10","money":"0.001","fee":"0.5","fee_symbol":"BIX","pay_bix":1}]},"cmd":"orderpending/orderDetail"}`
+	var res OrderFillsResult
+	if err := json.Unmarshal([]byte(data), &res); err != nil {
+		t.Fatal(err)
+	}
+	fill := res.Result.OrderList[0]
+	if fill.RelayID != 612216386 || fill.OrderSide != OrderSideSell || fill.PayBix != PayBixYes || fill.FeeSymbol != "BIX" {
+		t.Fatal(fill)
+	}
+}
+
+func TestWithdrawBodyValidate(t *testing.T) {
+	body := &WithdrawBody{TradePwd: "pwd", CoinSymbol: "BIX", Amount: 10, Addr: "0xabc"}
+	if err := body.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	body.Amount = 0
+	if err := body.Validate(); err == nil {
+		t.Fatal("zero amount should fail")
+	}
+	if err := (&TransferListBody{Page: 0, Size: 10}).Validate(); err == nil {
+		t.Fatal("page 0 should fail")
+	}
+}
@@ -0,0 +1,155 @@
+package clock
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Clock 时间来源 交易所服务用它生成签名时间戳 *Sync 实现了该接口
+type Clock interface {
+	Now() time.Time
+}
+
+// Source 查询交易所服务器时间 如 (*fcoin.FcoinService).GetServerTime
+type Source func() (time.Time, error)
+
+// DefaultSamples 每次同步的采样次数
+const DefaultSamples = 5
+
+// Sync 测量本机与交易所服务器的时差 并用时差修正本机时间
+// 每次同步采样多次 取往返时间最短的一次 时差 = 服务器时间 - (发送时间 + 往返时间/2)
+// 误差为往返时间的一半
+type Sync struct {
+	source  Source
+	Samples int
+
+	mu          sync.RWMutex
+	offset      time.Duration
+	uncertainty time.Duration
+	rtt         time.Duration
+	synced      time.Time
+
+	stop chan struct{}
+	done chan struct{}
+	now  func() time.Time
+}
+
+// New 新建时钟同步 需要调用 Sync 或 Start 之后时差才生效
+func New(source Source) *Sync {
+	return &Sync{
+		source:  source,
+		Samples: DefaultSamples,
+		now:     time.Now,
+	}
+}
+
+// Sync 立即同步一次 全部采样失败时返回最后一个错误 原有时差保持不变
+func (s *Sync) Sync() error {
+	samples := s.Samples
+	if samples <= 0 {
+		samples = 1
+	}
+	var (
+		best    time.Duration
+		bestRTT time.Duration = -1
+		lastErr error
+	)
+	for i := 0; i < samples; i++ {
+		t0 := s.now()
+		server, err := s.source()
+		t1 := s.now()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		rtt := t1.Sub(t0)
+		if rtt < 0 {
+			continue
+		}
+		if bestRTT < 0 || rtt < bestRTT {
+			bestRTT = rtt
+			best = server.Sub(t0.Add(rtt / 2))
+		}
+	}
+	if bestRTT < 0 {
+		if lastErr == nil {
+			lastErr = errors.New("clock sync got no valid sample")
+		}
+		return lastErr
+	}
+
+	s.mu.Lock()
+	s.offset = best
+	s.rtt = bestRTT
+	s.uncertainty = bestRTT / 2
+	s.synced = s.now()
+	s.mu.Unlock()
+	return nil
+}
+
+// Now 修正后的时间 即本机时间加上时差
+func (s *Sync) Now() time.Time {
+	s.mu.RLock()
+	offset := s.offset
+	s.mu.RUnlock()
+	return s.now().Add(offset)
+}
+
+// Offset 当前时差及其误差 服务器时间 ≈ 本机时间 + offset ± uncertainty
+func (s *Sync) Offset() (offset, uncertainty time.Duration) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.offset, s.uncertainty
+}
+
+// RTT 最近一次同步采用的往返时间
+func (s *Sync) RTT() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.rtt
+}
+
+// LastSync 最近一次成功同步的本机时间 从未同步时为零值
+func (s *Sync) LastSync() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.synced
+}
+
+// Start 立即同步一次 之后每隔 interval 重新同步 onError 可以为 nil
+func (s *Sync) Start(interval time.Duration, onError func(error)) {
+	s.Stop()
+	stop, done := make(chan struct{}), make(chan struct{})
+	s.mu.Lock()
+	s.stop, s.done = stop, done
+	s.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := s.Sync(); err != nil && onError != nil {
+				onError(err)
+			}
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop 停止定时同步 保留最后的时差
+func (s *Sync) Stop() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
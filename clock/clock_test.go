@@ -0,0 +1,93 @@
+package clock
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeClock 每次读取前进 step
+type fakeClock struct {
+	t    time.Time
+	step time.Duration
+}
+
+func (f *fakeClock) now() time.Time {
+	f.t = f.t.Add(f.step)
+	return f.t
+}
+
+func TestSyncPicksLowestRTT(t *testing.T) {
+	local := &fakeClock{t: time.Unix(1000, 0)}
+	delays := []time.Duration{80, 20, 50}
+	call := 0
+	s := New(func() (time.Time, error) {
+		d := delays[call%len(delays)] * time.Millisecond
+		call++
+		// 请求路上花一半时间 服务器比本机快 3 秒
+		local.t = local.t.Add(d / 2)
+		server := local.t.Add(3 * time.Second)
+		local.t = local.t.Add(d / 2)
+		return server, nil
+	})
+	s.now = local.now
+	s.Samples = 3
+	if err := s.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	offset, uncertainty := s.Offset()
+	if offset != 3*time.Second {
+		t.Fatalf("offset %v", offset)
+	}
+	if uncertainty != 10*time.Millisecond || s.RTT() != 20*time.Millisecond {
+		t.Fatalf("uncertainty %v rtt %v", uncertainty, s.RTT())
+	}
+	before := local.t
+	if got := s.Now(); got.Sub(before) != 3*time.Second {
+		t.Fatalf("now %v local %v", got, before)
+	}
+}
+
+func TestSyncErrorKeepsOffset(t *testing.T) {
+	fail := false
+	s := New(func() (time.Time, error) {
+		if fail {
+			return time.Time{}, errors.New("timeout")
+		}
+		return time.Now().Add(time.Hour), nil
+	})
+	if err := s.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	offset, _ := s.Offset()
+	fail = true
+	if err := s.Sync(); err == nil {
+		t.Fatal("sync should fail")
+	}
+	if again, _ := s.Offset(); again != offset {
+		t.Fatalf("offset changed from %v to %v", offset, again)
+	}
+}
+
+func TestStartStop(t *testing.T) {
+	calls := make(chan struct{}, 10)
+	s := New(func() (time.Time, error) {
+		calls <- struct{}{}
+		return time.Now(), nil
+	})
+	s.Samples = 1
+	s.Start(5*time.Millisecond, nil)
+	<-calls
+	<-calls
+	s.Stop()
+	if s.LastSync().IsZero() {
+		t.Fatal("should have synced")
+	}
+}
+
+func TestSyncIsClock(t *testing.T) {
+	var c Clock = New(func() (time.Time, error) { return time.Now(), nil })
+	if d := time.Since(c.Now()); d < 0 || d > time.Second {
+		t.Fatalf("unsynced clock off by %v", d)
+	}
+}
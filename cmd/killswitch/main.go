@@ -0,0 +1,73 @@
+// killswitch 撤销所有已配置交易所的全部挂单 可选把余额市价卖成目标币种
+//
+// 密钥从环境变量读取 未配置的交易所跳过
+//
+//	FCOIN_KEY FCOIN_SECRET
+//	GATE_KEY GATE_SECRET
+//	BIBOX_KEY BIBOX_SECRET
+//
+// 用法
+//
+//	killswitch -dry-run
+//	killswitch -flatten -target usdt -keep btc,eth
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"go-exchange/batch"
+	"go-exchange/bibox"
+	"go-exchange/fcoin"
+	"go-exchange/gateio"
+	"go-exchange/venue"
+)
+
+func main() {
+	flatten := flag.Bool("flatten", false, "market-sell balances to -target after canceling")
+	target := flag.String("target", "usdt", "currency to sell into")
+	keep := flag.String("keep", "", "comma separated currencies not to sell")
+	dryRun := flag.Bool("dry-run", false, "only list what would be done")
+	concurrency := flag.Int("concurrency", batch.DefaultConcurrency, "concurrent cancels per exchange")
+	flag.Parse()
+
+	venues := configured()
+	if len(venues) == 0 {
+		fmt.Fprintln(os.Stderr, "no exchange configured, set FCOIN_KEY/FCOIN_SECRET, GATE_KEY/GATE_SECRET or BIBOX_KEY/BIBOX_SECRET")
+		os.Exit(2)
+	}
+
+	ks := &venue.KillSwitch{
+		Venues:  venues,
+		Flatten: *flatten,
+		Target:  *target,
+		DryRun:  *dryRun,
+		Options: batch.Options{Concurrency: *concurrency, Interval: fcoin.DefaultPageInterval},
+	}
+	if *keep != "" {
+		ks.Keep = strings.Split(*keep, ",")
+	}
+	report := ks.Run()
+	report.Write(os.Stdout)
+	if !report.OK() {
+		os.Exit(1)
+	}
+}
+
+func configured() []venue.Venue {
+	venues := make([]venue.Venue, 0)
+	if key, secret := os.Getenv("FCOIN_KEY"), os.Getenv("FCOIN_SECRET"); key != "" && secret != "" {
+		fs, _ := fcoin.NewFcoinService("https://api.fcoin.com", key, secret)
+		venues = append(venues, venue.NewFcoin(fs))
+	}
+	if key, secret := os.Getenv("GATE_KEY"), os.Getenv("GATE_SECRET"); key != "" && secret != "" {
+		venues = append(venues, venue.NewGate(gateio.NewService(key, secret)))
+	}
+	if key, secret := os.Getenv("BIBOX_KEY"), os.Getenv("BIBOX_SECRET"); key != "" && secret != "" {
+		bs, _ := bibox.NewBiboxService("https://api.bibox.com/", key, secret)
+		venues = append(venues, venue.NewBibox(bs))
+	}
+	return venues
+}
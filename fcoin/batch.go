@@ -0,0 +1,70 @@
+package fcoin
+
+import (
+	"go-exchange/batch"
+)
+
+// DefaultBatchOptions 批量请求的默认并发与间隔 满足每 10 秒 100 次的限制
+var DefaultBatchOptions = batch.Options{Concurrency: 4, Interval: DefaultPageInterval}
+
+// OrderRequest 批量下单中的一个订单 AccountType 为空时为现货账户
+type OrderRequest struct {
+	Symbol      string
+	Side        string
+	Type        string
+	Price       string
+	Amount      string
+	AccountType string
+}
+
+// BatchOrderResult 批量下单中一个订单的结果
+type BatchOrderResult struct {
+	OrderID string
+	Err     error
+}
+
+// BatchCancelResult 批量撤单中一个订单的结果
+type BatchCancelResult struct {
+	OrderID string
+	Success bool
+	Err     error
+}
+
+// CreateOrders 并发下单 结果与 orders 顺序一致 返回第一个错误
+// opts 为 nil 时使用 DefaultBatchOptions
+func (fs *FcoinService) CreateOrders(orders []OrderRequest, opts *batch.Options) ([]BatchOrderResult, error) {
+	res := make([]BatchOrderResult, len(orders))
+	errs := batch.Run(len(orders), batchOptions(opts), func(i int) error {
+		o := orders[i]
+		id, err := fs.createOrder(o.Symbol, o.Side, o.Type, o.Price, o.Amount, o.AccountType)
+		res[i].OrderID = id
+		return err
+	})
+	for i, err := range errs {
+		res[i].Err = err
+	}
+	return res, batch.First(errs)
+}
+
+// CancelOrders 并发撤单 结果与 orderIDs 顺序一致 返回第一个错误
+// opts 为 nil 时使用 DefaultBatchOptions
+func (fs *FcoinService) CancelOrders(orderIDs []string, opts *batch.Options) ([]BatchCancelResult, error) {
+	res := make([]BatchCancelResult, len(orderIDs))
+	errs := batch.Run(len(orderIDs), batchOptions(opts), func(i int) error {
+		ok, err := fs.CancelOrder(orderIDs[i])
+		res[i].Success = ok
+		return err
+	})
+	for i, err := range errs {
+		res[i].OrderID = orderIDs[i]
+		res[i].Err = err
+	}
+	return res, batch.First(errs)
+}
+
+func batchOptions(opts *batch.Options) batch.Options {
+	if opts == nil {
+		return DefaultBatchOptions
+	}
+	return *opts
+}
@@ -0,0 +1,38 @@
+package fcoin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-exchange/batch"
+)
+
+func TestCreateOrders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := make(map[string]string)
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["price"] == "0" {
+			fmt.Fprint(w, `{"status":1016,"msg":"bad price"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"status":0,"data":"id-%s-%s"}`, body["price"], body["account_type"])
+	}))
+	defer server.Close()
+
+	fs, _ := NewFcoinService(server.URL, "key", "secret")
+	orders := []OrderRequest{
+		{Symbol: "btcusdt", Side: SideBuy, Type: "limit", Price: "1", Amount: "1"},
+		{Symbol: "btcusdt", Side: SideBuy, Type: "limit", Price: "0", Amount: "1"},
+		{Symbol: "btcusdt", Side: SideBuy, Type: "limit", Price: "3", Amount: "1", AccountType: AccountTypeMargin},
+	}
+	res, err := fs.CreateOrders(orders, &batch.Options{Concurrency: 3})
+	if err == nil {
+		t.Fatal("want first error")
+	}
+	if res[0].OrderID != "id-1-" || res[0].Err != nil || res[1].Err == nil || res[2].OrderID != "id-3-margin" {
+		t.Fatal(res)
+	}
+}
@@ -0,0 +1,142 @@
+package fcoin
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// 订单方向
+const (
+	SideBuy  = "buy"
+	SideSell = "sell"
+)
+
+// ErrNotEnoughDepth 深度不足以成交指定数量
+var ErrNotEnoughDepth = errors.New("not enough depth")
+
+// PriceLevel 一档深度
+type PriceLevel struct {
+	Price  float64
+	Amount float64
+}
+
+// Book 按档位整理后的深度 Bids 价格从高到低 Asks 价格从低到高
+type Book struct {
+	Bids []PriceLevel
+	Asks []PriceLevel
+}
+
+// Book 把价格和数量交替排列的 bids asks 整理成档位
+// 长度必须为偶数 买盘价格递减 卖盘价格递增 且买一价必须小于卖一价
+func (md *MarketDepth) Book() (*Book, error) {
+	bids, err := pairLevels("bids", md.Bids, true)
+	if err != nil {
+		return nil, err
+	}
+	asks, err := pairLevels("asks", md.Asks, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(bids) > 0 && len(asks) > 0 && bids[0].Price >= asks[0].Price {
+		return nil, fmt.Errorf("crossed book: bid %v >= ask %v", bids[0].Price, asks[0].Price)
+	}
+	return &Book{Bids: bids, Asks: asks}, nil
+}
+
+func pairLevels(name string, flat []float64, descending bool) ([]PriceLevel, error) {
+	if len(flat)%2 != 0 {
+		return nil, fmt.Errorf("%s has odd length %d", name, len(flat))
+	}
+	levels := make([]PriceLevel, 0, len(flat)/2)
+	for i := 0; i < len(flat); i += 2 {
+		l := PriceLevel{Price: flat[i], Amount: flat[i+1]}
+		if l.Price <= 0 || l.Amount < 0 {
+			return nil, fmt.Errorf("%s level %d invalid: price %v amount %v", name, i/2, l.Price, l.Amount)
+		}
+		if n := len(levels); n > 0 {
+			prev := levels[n-1].Price
+			if (descending && l.Price >= prev) || (!descending && l.Price <= prev) {
+				return nil, fmt.Errorf("%s level %d price %v not monotonic after %v", name, i/2, l.Price, prev)
+			}
+		}
+		levels = append(levels, l)
+	}
+	return levels, nil
+}
+
+// BestBid 买一 当前可卖出的最高价格
+func (b *Book) BestBid() (PriceLevel, bool) {
+	if len(b.Bids) == 0 {
+		return PriceLevel{}, false
+	}
+	return b.Bids[0], true
+}
+
+// BestAsk 卖一 当前可买入的最低价格
+func (b *Book) BestAsk() (PriceLevel, bool) {
+	if len(b.Asks) == 0 {
+		return PriceLevel{}, false
+	}
+	return b.Asks[0], true
+}
+
+// Mid 买一卖一的中间价 任一边为空时返回 0
+func (b *Book) Mid() float64 {
+	bid, okBid := b.BestBid()
+	ask, okAsk := b.BestAsk()
+	if !okBid || !okAsk {
+		return 0
+	}
+	return (bid.Price + ask.Price) / 2
+}
+
+// Microprice 按买一卖一数量加权的价格 买一量越大越靠近卖一价
+func (b *Book) Microprice() float64 {
+	bid, okBid := b.BestBid()
+	ask, okAsk := b.BestAsk()
+	if !okBid || !okAsk {
+		return 0
+	}
+	total := bid.Amount + ask.Amount
+	if total == 0 {
+		return (bid.Price + ask.Price) / 2
+	}
+	return (bid.Price*ask.Amount + ask.Price*bid.Amount) / total
+}
+
+// SpreadBps 买卖价差 以中间价的万分之一为单位
+func (b *Book) SpreadBps() float64 {
+	mid := b.Mid()
+	if mid == 0 {
+		return 0
+	}
+	return (b.Asks[0].Price - b.Bids[0].Price) / mid * 1e4
+}
+
+// VWAP 市价成交 amount 数量的成交均价 buy 吃卖盘 sell 吃买盘
+// 深度不足时返回 ErrNotEnoughDepth
+func (b *Book) VWAP(side string, amount float64) (float64, error) {
+	var levels []PriceLevel
+	switch side {
+	case SideBuy:
+		levels = b.Asks
+	case SideSell:
+		levels = b.Bids
+	default:
+		return 0, fmt.Errorf("unknown side %q", side)
+	}
+	if amount <= 0 {
+		return 0, fmt.Errorf("amount must be positive, got %v", amount)
+	}
+	left, cost := amount, 0.0
+	for _, l := range levels {
+		fill := math.Min(left, l.Amount)
+		cost += fill * l.Price
+		left -= fill
+		if left <= 0 {
+			return cost / amount, nil
+		}
+	}
+	return 0, ErrNotEnoughDepth
+}
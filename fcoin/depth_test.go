@@ -0,0 +1,54 @@
+package fcoin
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMarketDepthBook(t *testing.T) {
+	md := &MarketDepth{
+		Bids: []float64{99, 3, 98, 5},
+		Asks: []float64{101, 1, 102, 4},
+	}
+	book, err := md.Book()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if book.Bids[1] != (PriceLevel{98, 5}) || book.Asks[0] != (PriceLevel{101, 1}) {
+		t.Fatal(book)
+	}
+	if book.Mid() != 100 {
+		t.Fatal(book.Mid())
+	}
+	// 买一量 3 卖一量 1 价格偏向卖一
+	if mp := book.Microprice(); math.Abs(mp-100.5) > 1e-9 {
+		t.Fatal(mp)
+	}
+	if bps := book.SpreadBps(); math.Abs(bps-200) > 1e-9 {
+		t.Fatal(bps)
+	}
+	if p, err := book.VWAP(SideBuy, 3); err != nil || math.Abs(p-(101+2*102)/3.0) > 1e-9 {
+		t.Fatal(p, err)
+	}
+	if p, err := book.VWAP(SideSell, 3); err != nil || p != 99 {
+		t.Fatal(p, err)
+	}
+	if _, err := book.VWAP(SideSell, 9); err != ErrNotEnoughDepth {
+		t.Fatal(err)
+	}
+}
+
+func TestMarketDepthBookInvalid(t *testing.T) {
+	cases := []*MarketDepth{
+		{Bids: []float64{99}, Asks: []float64{101, 1}},
+		{Bids: []float64{98, 1, 99, 1}, Asks: []float64{101, 1}},
+		{Bids: []float64{99, 1}, Asks: []float64{102, 1, 101, 1}},
+		{Bids: []float64{101, 1}, Asks: []float64{100, 1}},
+		{Bids: []float64{0, 1}},
+	}
+	for i, md := range cases {
+		if _, err := md.Book(); err == nil {
+			t.Errorf("case %d should fail", i)
+		}
+	}
+}
@@ -13,6 +13,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"go-exchange/clock"
 )
 
 // FcoinService service for call fcoin api
@@ -20,6 +22,7 @@ type FcoinService struct {
 	URL       string
 	APIKey    string
 	SecretKey string
+	clock     clock.Clock
 }
 
 // NewFcoinService  New A fcoin Service Object
@@ -36,7 +39,7 @@ func NewFcoinService(url, apiKey, secret string) (*FcoinService, error) {
 func (fs *FcoinService) authorization(method, path string, params, body url.Values) (json.RawMessage, error) {
 	method = strings.ToUpper(method)
 	sURI := sortedURI(fs.URL+path, params)
-	ts := strconv.Itoa(int(time.Now().Unix()) * 1e3)
+	ts := strconv.FormatInt(fs.now().UnixNano()/1e6, 10)
 	sBody := sortedBody(body)
 
 	src := []byte(method + sURI + ts + sBody)
@@ -84,6 +87,18 @@ func (fs *FcoinService) authorization(method, path string, params, body url.Valu
 	return res.Data, nil
 }
 
+// SetClock 设置签名使用的时间来源 如 *clock.Sync 为 nil 时使用本机时间
+func (fs *FcoinService) SetClock(c clock.Clock) {
+	fs.clock = c
+}
+
+func (fs *FcoinService) now() time.Time {
+	if fs.clock != nil {
+		return fs.clock.Now()
+	}
+	return time.Now()
+}
+
 func (fs *FcoinService) public(method, path string, params, body url.Values) (json.RawMessage, error) {
 	method = strings.ToUpper(method)
 	sURI := sortedURI(fs.URL+path, params)
@@ -182,20 +197,7 @@ func (fs *FcoinService) GetSymbols() ([]Symbol, error) {
 	return res, nil
 }
 
-// GetMarketTicker 获取 ticker 数据
-/**
-  "最新成交价",
-  "最近一笔成交的成交量",
-  "最大买一价",
-  "最大买一量",
-  "最小卖一价",
-  "最小卖一量",
-  "24小时前成交价",
-  "24小时内最高价",
-  "24小时内最低价",
-  "24小时内基准货币成交量, 如 btcusdt 中 btc 的量",
-  "24小时内计价货币成交量, 如 btcusdt 中 usdt 的量"
-*/
+// GetMarketTicker 获取 ticker 数据 各字段含义见 Ticker
 func (fs *FcoinService) GetMarketTicker(symbol string) (*MarketTicker, error) {
 	path := `/v2/market/ticker/` + symbol
 	data, err := fs.public("GET", path, nil, nil)
@@ -352,12 +354,20 @@ pending_cancel	撤销已提交
 
 // CreateOrder 创建新的订单 返回订单ID
 func (fs *FcoinService) CreateOrder(symbol, side, orderType, price, amount string) (string, error) {
+	return fs.createOrder(symbol, side, orderType, price, amount, "")
+}
+
+// createOrder accountType 为空时为现货账户
+func (fs *FcoinService) createOrder(symbol, side, orderType, price, amount, accountType string) (string, error) {
 	values := url.Values{}
 	values.Add("symbol", symbol)  // 交易对
 	values.Add("side", side)      // 交易方向
 	values.Add("type", orderType) // 订单类型
 	values.Add("price", price)    // 价格
 	values.Add("amount", amount)  // 下单量
+	if accountType != "" {
+		values.Add("account_type", accountType) // 账户类型
+	}
 
 	path := `/v2/orders`
 	data, err := fs.authorization("POST", path, nil, values)
@@ -375,13 +385,8 @@ func (fs *FcoinService) CreateOrder(symbol, side, orderType, price, amount strin
 }
 
 // GetOrders 查询订单列表
-func (fs *FcoinService) GetOrders(symbol, states, before, after, limit string) ([]OrderInformation, error) {
-	values := url.Values{}
-	values.Add("symbol", symbol) // 交易对
-	values.Add("states", states) // 订单状态
-	values.Add("before", before) // 查询某个页码之前的订单
-	values.Add("after", after)   // 查询某个页码之后的订单
-	values.Add("limit", limit)   // 每页的订单数量，默认为 20 条
+func (fs *FcoinService) GetOrders(query *OrderQuery) ([]OrderInformation, error) {
+	values := query.values()
 
 	path := `/v2/orders`
 	data, err := fs.authorization("GET", path, values, nil)
@@ -449,19 +454,23 @@ func (fs *FcoinService) OrderMatchResult(orderID string) ([]OrderMatchResult, er
 	return res, nil
 }
 
+// GetCurrentMarketPrice 买一卖一的中间价 priceDecimal 大于 0 时 中间价按精度取整后与买一或卖一相同则返回错误
 func (fs *FcoinService) GetCurrentMarketPrice(symbol string, priceDecimal int) (float64, error) {
 	depth, err := fs.GetMarketDepth("L20", symbol)
 	if err != nil {
 		return 0.0, err
 	}
-	if len(depth.Bids) <= 1 || len(depth.Asks) <= 1 { // bids和asks成对出现 长度小于1越界
-		return 0.0, fmt.Errorf("return wrong error")
+	book, err := depth.Book()
+	if err != nil {
+		return 0.0, err
+	}
+	if len(book.Bids) == 0 || len(book.Asks) == 0 {
+		return 0.0, fmt.Errorf("%s depth has empty side", symbol)
 	}
 
-	bid := depth.Bids[0] // 卖单最小价 当前可买入的最低价格
-	ask := depth.Asks[0] // 买单最大价 当前可卖出的最高价格
-	//return (bid + ask) / 2, nil
-	price := bid + (ask-bid)*1/2
+	bid := book.Bids[0].Price // 买一价 当前可卖出的最高价格
+	ask := book.Asks[0].Price // 卖一价 当前可买入的最低价格
+	price := book.Mid()
 	if priceDecimal > 0 {
 		if priceStr := strconv.FormatFloat(price, 'f', priceDecimal, 64); priceStr == strconv.FormatFloat(bid, 'f', priceDecimal, 64) ||
 			priceStr == strconv.FormatFloat(ask, 'f', priceDecimal, 64) {
@@ -506,16 +515,14 @@ func (fs *FcoinService) HasEnoughAssets(coin string, amount float64) (bool, erro
 	return enoughFlag, nil
 }
 
+// IsOrderFinished 订单是否已结束 见 OrderState.Terminal
 func (fs *FcoinService) IsOrderFinished(orderID string) (bool, error) {
 	order, err := fs.GetOrderByID(orderID)
 	if err != nil {
 		return false, err
 	}
 
-	if order.State == "filled" || order.State == "canceled" || order.State == "partial_canceled" { // 卖出完全交易
-		return true, nil
-	}
-	return false, nil
+	return order.State.Terminal(), nil
 }
 
 func GetCurrentCoinType(symbol string) (string, string) {
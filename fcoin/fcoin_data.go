@@ -3,6 +3,8 @@ package fcoin
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 )
 
 var (
@@ -23,12 +25,92 @@ type Symbol struct {
 	AmountDecimal int    `json:"amount_decimal"`
 }
 
+// MarketTicker REST 接口和推送的 ticker 消息结构相同 type 为 ticker.$symbol
 type MarketTicker struct {
-	Type   string    `json:"type"`
-	Seq    int       `json:"seq"`
-	Ticker []float64 `json:"ticker"`
+	Type   string `json:"type"`
+	Seq    int    `json:"seq"`
+	Ticker Ticker `json:"ticker"`
 }
 
+// Symbol 从 type 中取出交易对
+func (mt *MarketTicker) Symbol() string {
+	return strings.TrimPrefix(mt.Type, "ticker.")
+}
+
+// Ticker 行情 接口按位置返回 11 个数值
+type Ticker struct {
+	Last        float64 // 最新成交价
+	LastSize    float64 // 最近一笔成交的成交量
+	Bid         float64 // 最大买一价
+	BidSize     float64 // 最大买一量
+	Ask         float64 // 最小卖一价
+	AskSize     float64 // 最小卖一量
+	Open24h     float64 // 24小时前成交价
+	High24h     float64 // 24小时内最高价
+	Low24h      float64 // 24小时内最低价
+	BaseVolume  float64 // 24小时内基准货币成交量, 如 btcusdt 中 btc 的量
+	QuoteVolume float64 // 24小时内计价货币成交量, 如 btcusdt 中 usdt 的量
+}
+
+// tickerFields ticker 数组的长度
+const tickerFields = 11
+
+// ParseTicker 按位置解析 ticker 数组
+func ParseTicker(values []float64) (Ticker, error) {
+	if len(values) < tickerFields {
+		return Ticker{}, fmt.Errorf("ticker want %d values got %d", tickerFields, len(values))
+	}
+	return Ticker{
+		Last:        values[0],
+		LastSize:    values[1],
+		Bid:         values[2],
+		BidSize:     values[3],
+		Ask:         values[4],
+		AskSize:     values[5],
+		Open24h:     values[6],
+		High24h:     values[7],
+		Low24h:      values[8],
+		BaseVolume:  values[9],
+		QuoteVolume: values[10],
+	}, nil
+}
+
+// UnmarshalJSON 从位置数组解析
+func (t *Ticker) UnmarshalJSON(data []byte) error {
+	var values []float64
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	ticker, err := ParseTicker(values)
+	if err != nil {
+		return err
+	}
+	*t = ticker
+	return nil
+}
+
+// MarshalJSON 编码回位置数组
+func (t Ticker) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Values())
+}
+
+// Values 按接口的顺序返回数组
+func (t Ticker) Values() []float64 {
+	return []float64{
+		t.Last, t.LastSize, t.Bid, t.BidSize, t.Ask, t.AskSize,
+		t.Open24h, t.High24h, t.Low24h, t.BaseVolume, t.QuoteVolume,
+	}
+}
+
+// Change24h 24小时涨跌幅 百分比 24小时前成交价为 0 时返回 0
+func (t Ticker) Change24h() float64 {
+	if t.Open24h == 0 {
+		return 0
+	}
+	return (t.Last - t.Open24h) / t.Open24h * 100
+}
+
+// MarketDepth 深度 bids asks 中价格和数量交替排列 用 Book 整理成档位
 type MarketDepth struct {
 	Type string    `json:"type"`
 	Ts   int64     `json:"ts"`
@@ -65,21 +147,6 @@ type AccountBalance struct {
 	Balance   string `json:"balance"`
 }
 
-type OrderInformation struct {
-	ID            string `json:"id"`
-	Symbol        string `json:"symbol"`
-	Type          string `json:"type"`
-	Side          string `json:"side"`
-	Price         string `json:"price"`
-	Amount        string `json:"amount"`
-	State         string `json:"state"`
-	ExecutedValue string `json:"executed_value"`
-	FillFees      string `json:"fill_fees"`
-	FilledAmount  string `json:"filled_amount"`
-	CreatedAt     int    `json:"created_at"`
-	Source        string `json:"source"`
-}
-
 type OrderMatchResult struct {
 	Price        string `json:"price"`
 	FillFees     string `json:"fill_fees"`
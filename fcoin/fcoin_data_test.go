@@ -0,0 +1,41 @@
+package fcoin
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestMarketTickerUnmarshal(t *testing.T) {
+	data := `{"type":"ticker.btcusdt","seq":680035,"ticker":[7140.89,1.0,7140.88,0.0226,7140.89,0.5,7000.0,7200.0,6900.0,12.5,89000.0]}`
+	mt := new(MarketTicker)
+	if err := json.Unmarshal([]byte(data), mt); err != nil {
+		t.Fatal(err)
+	}
+	if mt.Symbol() != "btcusdt" {
+		t.Fatal(mt.Symbol())
+	}
+	tk := mt.Ticker
+	if tk.Bid != 7140.88 || tk.BidSize != 0.0226 || tk.BaseVolume != 12.5 || tk.QuoteVolume != 89000 {
+		t.Fatal(tk)
+	}
+	if math.Abs(tk.Change24h()-2.0127) > 1e-4 {
+		t.Fatal(tk.Change24h())
+	}
+
+	bs, err := json.Marshal(mt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again := new(MarketTicker)
+	if err := json.Unmarshal(bs, again); err != nil || again.Ticker != tk {
+		t.Fatal(string(bs), err)
+	}
+}
+
+func TestMarketTickerShort(t *testing.T) {
+	mt := new(MarketTicker)
+	if err := json.Unmarshal([]byte(`{"ticker":[1,2,3]}`), mt); err == nil {
+		t.Fatal("short ticker should fail")
+	}
+}
@@ -171,7 +171,7 @@ func TestFcoinService_GetOrders(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	cs, err := fs.GetOrders("gtcft", "partial_canceled","","0","20")
+	cs, err := fs.GetOrders(&OrderQuery{Symbol: "gtcft", States: []OrderState{OrderPartialCanceled}, Limit: 20})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -311,4 +311,32 @@ func TestUrlValuesToJSON(t *testing.T) {
 	values.Add("a", "A")
 	values.Add("b", "B")
 	t.Log(urlValuesToJSON(values))
-}
\ No newline at end of file
+}
+
+func TestFcoinService_GetLeveragedAccounts(t *testing.T) {
+	fs, err := NewFcoinService("https://api.fcoin.com", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	accounts, err := fs.GetLeveragedAccounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range accounts {
+		t.Log(a)
+	}
+}
+
+func TestFcoinService_GetLoans(t *testing.T) {
+	fs, err := NewFcoinService("https://api.fcoin.com", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	loans, err := fs.GetLoans(&LoanQuery{AccountType: "btcusdt", Limit: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, l := range loans {
+		t.Log(l)
+	}
+}
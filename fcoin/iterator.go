@@ -0,0 +1,312 @@
+package fcoin
+
+import (
+	"strconv"
+	"time"
+
+	"go-exchange/batch"
+)
+
+// DefaultPageInterval 翻页请求之间的默认最小间隔 fcoin 限制每个用户每 10 秒 100 次请求
+const DefaultPageInterval = 100 * time.Millisecond
+
+// MaxOrderLimit GetOrders 每页的最大数量
+const MaxOrderLimit = 100
+
+// OrderIterator 按创建时间从新到旧遍历订单 每次请求一页 用完再取下一页
+// 游标包含上一页最早订单的那一毫秒 同一时间的订单按 ID 跳过已读的
+// 整页都是已读订单时加大每页数量越过它们 返回数量小于每页数量时结束
+type OrderIterator struct {
+	batch.Pacer
+	limit  int
+	size   int // 当前每页数量
+	before time.Time
+	fetch  func(before time.Time, limit int) ([]OrderInformation, error)
+	seen   map[string]bool
+	buf    []OrderInformation
+	cur    OrderInformation
+	done   bool
+	err    error
+}
+
+// NewOrderIterator 新建订单迭代器 limit 为 0 时每页 20 条
+func (fs *FcoinService) NewOrderIterator(symbol string, states []OrderState, limit int) *OrderIterator {
+	if limit <= 0 {
+		limit = 20
+	}
+	return &OrderIterator{
+		Pacer: batch.Pacer{Interval: DefaultPageInterval},
+		limit: limit,
+		fetch: func(before time.Time, limit int) ([]OrderInformation, error) {
+			return fs.GetOrders(&OrderQuery{Symbol: symbol, States: states, Before: before, Limit: limit})
+		},
+		seen: make(map[string]bool),
+	}
+}
+
+// Before 从指定时间之前开始遍历
+func (it *OrderIterator) Before(t time.Time) *OrderIterator {
+	it.before = t
+	return it
+}
+
+// Next 取下一个订单 没有更多或出错时返回 false
+func (it *OrderIterator) Next() bool {
+	for len(it.buf) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		if it.size < it.limit {
+			it.size = it.limit
+		}
+		it.Wait()
+		page, err := it.fetch(it.before, it.size)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		var oldest time.Time
+		fresh := 0
+		for _, o := range page {
+			if oldest.IsZero() || o.CreatedAt.Before(oldest) {
+				oldest = o.CreatedAt
+			}
+			if !it.seen[o.ID] {
+				it.seen[o.ID] = true
+				it.buf = append(it.buf, o)
+				fresh++
+			}
+		}
+		switch {
+		case len(page) < it.size || oldest.IsZero():
+			it.done = true
+		case fresh > 0:
+			// 已越过上一页的最早时间 恢复正常的每页数量
+			if it.before.IsZero() || oldest.Before(it.before.Add(-time.Millisecond)) {
+				it.size = it.limit
+			}
+		case it.size >= MaxOrderLimit:
+			// 同一毫秒的订单超过每页最大数量 无法再往前翻
+			it.done = true
+		default:
+			it.size *= 2
+			if it.size > MaxOrderLimit {
+				it.size = MaxOrderLimit
+			}
+		}
+		it.before = oldest.Add(time.Millisecond)
+	}
+	it.cur, it.buf = it.buf[0], it.buf[1:]
+	return true
+}
+
+// Order 当前订单
+func (it *OrderIterator) Order() OrderInformation {
+	return it.cur
+}
+
+// Err 遍历过程中的错误
+func (it *OrderIterator) Err() error {
+	return it.err
+}
+
+// Collect 取出 [from, to] 时间范围内的所有订单
+func (it *OrderIterator) Collect(from, to time.Time) ([]OrderInformation, error) {
+	it.Before(to.Add(time.Millisecond))
+	res := make([]OrderInformation, 0)
+	for it.Next() {
+		o := it.Order()
+		if o.CreatedAt.Before(from) {
+			break
+		}
+		if !o.CreatedAt.After(to) {
+			res = append(res, o)
+		}
+	}
+	return res, it.Err()
+}
+
+// TradeIterator 按 ID 从新到旧遍历市场成交
+type TradeIterator struct {
+	batch.Pacer
+	limit  int
+	before string
+	fetch  func(before string, limit int) ([]MarketTrade, error)
+	seen   map[int]bool
+	buf    []MarketTrade
+	cur    MarketTrade
+	done   bool
+	err    error
+}
+
+// NewTradeIterator 新建成交迭代器 limit 为 0 时每页 20 条
+func (fs *FcoinService) NewTradeIterator(symbol string, limit int) *TradeIterator {
+	if limit <= 0 {
+		limit = 20
+	}
+	return &TradeIterator{
+		Pacer: batch.Pacer{Interval: DefaultPageInterval},
+		limit: limit,
+		fetch: func(before string, limit int) ([]MarketTrade, error) {
+			return fs.GetMarketTrades(symbol, before, limit)
+		},
+		seen: make(map[int]bool),
+	}
+}
+
+// Next 取下一条成交 没有更多或出错时返回 false
+func (it *TradeIterator) Next() bool {
+	for len(it.buf) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		it.Wait()
+		page, err := it.fetch(it.before, it.limit)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		if len(page) < it.limit {
+			it.done = true
+		}
+		oldest := -1
+		for _, t := range page {
+			if oldest < 0 || t.ID < oldest {
+				oldest = t.ID
+			}
+			if !it.seen[t.ID] {
+				it.seen[t.ID] = true
+				it.buf = append(it.buf, t)
+			}
+		}
+		next := strconv.Itoa(oldest)
+		if oldest < 0 || next == it.before {
+			it.done = true
+		}
+		it.before = next
+	}
+	it.cur, it.buf = it.buf[0], it.buf[1:]
+	return true
+}
+
+// Trade 当前成交
+func (it *TradeIterator) Trade() MarketTrade {
+	return it.cur
+}
+
+// Err 遍历过程中的错误
+func (it *TradeIterator) Err() error {
+	return it.err
+}
+
+// Collect 取出 [from, to] 时间范围内的所有成交 成交只能按 ID 翻页 所以从最新一页开始
+func (it *TradeIterator) Collect(from, to time.Time) ([]MarketTrade, error) {
+	fromMs, toMs := from.UnixNano()/1e6, to.UnixNano()/1e6
+	res := make([]MarketTrade, 0)
+	for it.Next() {
+		t := it.Trade()
+		if t.Ts < fromMs {
+			break
+		}
+		if t.Ts <= toMs {
+			res = append(res, t)
+		}
+	}
+	return res, it.Err()
+}
+
+// CandleIterator 按时间从新到旧遍历 K 线 Candle ID 即开盘时间 unix 秒
+type CandleIterator struct {
+	batch.Pacer
+	limit  int
+	before string
+	fetch  func(before string, limit int) ([]MarketCandle, error)
+	seen   map[int]bool
+	buf    []MarketCandle
+	cur    MarketCandle
+	done   bool
+	err    error
+}
+
+// NewCandleIterator 新建 K 线迭代器 limit 为 0 时每页 20 条
+func (fs *FcoinService) NewCandleIterator(resolution, symbol string, limit int) *CandleIterator {
+	if limit <= 0 {
+		limit = 20
+	}
+	return &CandleIterator{
+		Pacer: batch.Pacer{Interval: DefaultPageInterval},
+		limit: limit,
+		fetch: func(before string, limit int) ([]MarketCandle, error) {
+			return fs.GetMarketCandle(resolution, symbol, before, limit)
+		},
+		seen: make(map[int]bool),
+	}
+}
+
+// Before 从指定时间之前开始遍历
+func (it *CandleIterator) Before(t time.Time) *CandleIterator {
+	it.before = strconv.FormatInt(t.Unix(), 10)
+	return it
+}
+
+// Next 取下一根 K 线 没有更多或出错时返回 false
+func (it *CandleIterator) Next() bool {
+	for len(it.buf) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		it.Wait()
+		page, err := it.fetch(it.before, it.limit)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		if len(page) < it.limit {
+			it.done = true
+		}
+		oldest := -1
+		for _, c := range page {
+			if oldest < 0 || c.ID < oldest {
+				oldest = c.ID
+			}
+			if !it.seen[c.ID] {
+				it.seen[c.ID] = true
+				it.buf = append(it.buf, c)
+			}
+		}
+		next := strconv.Itoa(oldest)
+		if oldest < 0 || next == it.before {
+			it.done = true
+		}
+		it.before = next
+	}
+	it.cur, it.buf = it.buf[0], it.buf[1:]
+	return true
+}
+
+// Candle 当前 K 线
+func (it *CandleIterator) Candle() MarketCandle {
+	return it.cur
+}
+
+// Err 遍历过程中的错误
+func (it *CandleIterator) Err() error {
+	return it.err
+}
+
+// Collect 取出开盘时间在 [from, to] 范围内的所有 K 线
+func (it *CandleIterator) Collect(from, to time.Time) ([]MarketCandle, error) {
+	it.Before(to.Add(time.Second))
+	fromID, toID := int(from.Unix()), int(to.Unix())
+	res := make([]MarketCandle, 0)
+	for it.Next() {
+		c := it.Candle()
+		if c.ID < fromID {
+			break
+		}
+		if c.ID <= toID {
+			res = append(res, c)
+		}
+	}
+	return res, it.Err()
+}
@@ -0,0 +1,139 @@
+package fcoin
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+// fakeCandles 返回 ID 小于 before 的 K 线 ID 从 start 每根递减 60 秒 最小到 end
+func fakeCandles(start, end int) func(before string, limit int) ([]MarketCandle, error) {
+	return func(before string, limit int) ([]MarketCandle, error) {
+		page := make([]MarketCandle, 0)
+		b, err := strconv.Atoi(before)
+		for id := start; id >= end && len(page) < limit; id -= 60 {
+			if err == nil && id >= b {
+				continue
+			}
+			page = append(page, MarketCandle{ID: id})
+		}
+		return page, nil
+	}
+}
+
+func TestCandleIterator(t *testing.T) {
+	calls := 0
+	fetch := fakeCandles(6000, 60)
+	it := &CandleIterator{
+		limit: 7,
+		fetch: func(before string, limit int) ([]MarketCandle, error) {
+			calls++
+			return fetch(before, limit)
+		},
+		seen: make(map[int]bool),
+	}
+	count, last := 0, 6060
+	for it.Next() {
+		c := it.Candle()
+		if c.ID != last-60 {
+			t.Fatalf("candle %d after %d", c.ID, last)
+		}
+		last = c.ID
+		count++
+	}
+	if it.Err() != nil {
+		t.Fatal(it.Err())
+	}
+	if count != 100 || calls != 15 {
+		t.Fatalf("count %d calls %d", count, calls)
+	}
+}
+
+func TestCandleIteratorCollect(t *testing.T) {
+	it := &CandleIterator{limit: 10, fetch: fakeCandles(6000, 60), seen: make(map[int]bool)}
+	res, err := it.Collect(time.Unix(600, 0), time.Unix(1200, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 11 || res[0].ID != 1200 || res[10].ID != 600 {
+		t.Fatal(res)
+	}
+}
+
+func TestOrderIteratorSameTimestamp(t *testing.T) {
+	// a 到 e 在同一毫秒 f g 更早 before 不包含该时间
+	at := time.Unix(100, 0)
+	orders := []OrderInformation{
+		{ID: "a", CreatedAt: at}, {ID: "b", CreatedAt: at}, {ID: "c", CreatedAt: at},
+		{ID: "d", CreatedAt: at}, {ID: "e", CreatedAt: at},
+		{ID: "f", CreatedAt: at.Add(-time.Second)}, {ID: "g", CreatedAt: at.Add(-2 * time.Second)},
+	}
+	limits := make([]int, 0)
+	it := &OrderIterator{
+		limit: 2,
+		fetch: func(before time.Time, limit int) ([]OrderInformation, error) {
+			limits = append(limits, limit)
+			page := make([]OrderInformation, 0)
+			for _, o := range orders {
+				if len(page) < limit && (before.IsZero() || o.CreatedAt.Before(before)) {
+					page = append(page, o)
+				}
+			}
+			return page, nil
+		},
+		seen: make(map[string]bool),
+	}
+	ids := ""
+	for it.Next() {
+		ids += it.Order().ID
+	}
+	if ids != "abcdefg" || it.Err() != nil {
+		t.Fatalf("ids %s err %v limits %v", ids, it.Err(), limits)
+	}
+}
+
+func TestOrderIteratorStopsAtMaxLimit(t *testing.T) {
+	calls := 0
+	it := &OrderIterator{
+		limit: 50,
+		fetch: func(before time.Time, limit int) ([]OrderInformation, error) {
+			calls++
+			page := make([]OrderInformation, limit)
+			for i := range page {
+				page[i] = OrderInformation{ID: strconv.Itoa(i), CreatedAt: time.Unix(100, 0)}
+			}
+			return page, nil
+		},
+		seen: make(map[string]bool),
+	}
+	count := 0
+	for it.Next() {
+		count++
+	}
+	if count != MaxOrderLimit || calls != 4 {
+		t.Fatalf("count %d calls %d", count, calls)
+	}
+}
+
+func TestOrderIteratorCollect(t *testing.T) {
+	// 每秒一个订单 从 50 秒到 1 秒
+	fetch := func(before time.Time, limit int) ([]OrderInformation, error) {
+		page := make([]OrderInformation, 0)
+		for sec := 50; sec >= 1 && len(page) < limit; sec-- {
+			at := time.Unix(int64(sec), 0)
+			if !before.IsZero() && !at.Before(before) {
+				continue
+			}
+			page = append(page, OrderInformation{ID: strconv.Itoa(sec), CreatedAt: at})
+		}
+		return page, nil
+	}
+	it := &OrderIterator{limit: 4, fetch: fetch, seen: make(map[string]bool)}
+	res, err := it.Collect(time.Unix(10, 0), time.Unix(20, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 11 || res[0].ID != "20" || res[10].ID != "10" {
+		t.Fatal(res)
+	}
+}
@@ -0,0 +1,228 @@
+package fcoin
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// AccountTypeMargin 杠杆账户下单时的 account_type
+const AccountTypeMargin = "margin"
+
+// LeveragedAccount 杠杆账户 每个交易对一个账户 账户名即交易对 如 btcusdt
+type LeveragedAccount struct {
+	Open                             bool   `json:"open"`                                 // 是否已开通
+	LeveragedAccountType             string `json:"leveraged_account_type"`               // 账户类型 即交易对
+	Base                             string `json:"base"`                                 // 基准货币
+	Quote                            string `json:"quote"`                                // 计价货币
+	AvailableBaseCurrencyAmount      string `json:"available_base_currency_amount"`       // 基准货币可用
+	FrozenBaseCurrencyAmount         string `json:"frozen_base_currency_amount"`          // 基准货币冻结
+	AvailableQuoteCurrencyAmount     string `json:"available_quote_currency_amount"`      // 计价货币可用
+	FrozenQuoteCurrencyAmount        string `json:"frozen_quote_currency_amount"`         // 计价货币冻结
+	AvailableBaseCurrencyLoanAmount  string `json:"available_base_currency_loan_amount"`  // 基准货币可借
+	AvailableQuoteCurrencyLoanAmount string `json:"available_quote_currency_loan_amount"` // 计价货币可借
+	BlowUpPrice                      string `json:"blow_up_price"`                        // 爆仓价
+	RiskRate                         string `json:"risk_rate"`                            // 风险率
+	State                            string `json:"state"`                                // 账户状态 open close blow_up overrun
+}
+
+// LoanRecord 借币记录
+type LoanRecord struct {
+	ID                   string `json:"id"`
+	LeveragedAccountType string `json:"leveraged_account_type"`
+	Currency             string `json:"currency"`
+	RequestTime          int64  `json:"request_time"`  // 申请时间 毫秒
+	FinishedTime         int64  `json:"finished_time"` // 还清时间 毫秒
+	Amount               string `json:"amount"`        // 借币数量
+	UnpaidAmount         string `json:"unpaid_amount"` // 未还数量
+	InterestRate         string `json:"interest_rate"` // 日利率
+	InterestStartTime    int64  `json:"interest_start_time"`
+	UnpaidInterest       string `json:"unpaid_interest"` // 未还利息
+	State                string `json:"state"`           // submitted confirmed finished canceled
+	Source               string `json:"source"`
+}
+
+// RepaymentRecord 还币记录
+type RepaymentRecord struct {
+	ID                   string `json:"id"`
+	LeveragedLoanID      string `json:"leveraged_loan_id"`
+	LeveragedAccountType string `json:"leveraged_account_type"`
+	Currency             string `json:"currency"`
+	Amount               string `json:"amount"`         // 还币数量
+	Interest             string `json:"interest"`       // 其中利息
+	RepaymentTime        int64  `json:"repayment_time"` // 还币时间 毫秒
+	State                string `json:"state"`          // submitted success failed
+}
+
+// LoanQuery 借币记录查询条件 零值字段不发送
+type LoanQuery struct {
+	AccountType string // 杠杆账户 即交易对
+	Currency    string
+	State       string
+	Before      time.Time
+	After       time.Time
+	Limit       int
+}
+
+func (q *LoanQuery) values() url.Values {
+	values := url.Values{}
+	if q.AccountType != "" {
+		values.Add("account_type", q.AccountType)
+	}
+	if q.Currency != "" {
+		values.Add("currency", q.Currency)
+	}
+	if q.State != "" {
+		values.Add("state", q.State)
+	}
+	if !q.Before.IsZero() {
+		values.Add("before", strconv.FormatInt(unixMilli(q.Before), 10))
+	}
+	if !q.After.IsZero() {
+		values.Add("after", strconv.FormatInt(unixMilli(q.After), 10))
+	}
+	if q.Limit > 0 {
+		values.Add("limit", strconv.Itoa(q.Limit))
+	}
+	return values
+}
+
+// GetLeveragedAccounts 查询所有杠杆账户
+func (fs *FcoinService) GetLeveragedAccounts() ([]LeveragedAccount, error) {
+	path := `/v2/broker/leveraged_accounts`
+	data, err := fs.authorization("GET", path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]LeveragedAccount, 0)
+	err = json.Unmarshal(data, &res)
+	if err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// GetLeveragedAccount 查询指定交易对的杠杆账户
+func (fs *FcoinService) GetLeveragedAccount(accountType string) (*LeveragedAccount, error) {
+	values := url.Values{}
+	values.Add("account_type", accountType)
+
+	path := `/v2/broker/leveraged_accounts/account`
+	data, err := fs.authorization("GET", path, values, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := new(LeveragedAccount)
+	err = json.Unmarshal(data, res)
+	if err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// Borrow 在杠杆账户中借币 返回借币记录 ID
+func (fs *FcoinService) Borrow(accountType, currency, amount string) (string, error) {
+	values := url.Values{}
+	values.Add("account_type", accountType) // 杠杆账户
+	values.Add("currency", currency)        // 借入币种
+	values.Add("amount", amount)            // 借入数量
+
+	path := `/v2/broker/leveraged/loans`
+	data, err := fs.authorization("POST", path, nil, values)
+	if err != nil {
+		return "", err
+	}
+
+	loanID := ""
+	err = json.Unmarshal(data, &loanID)
+	if err != nil {
+		return loanID, err
+	}
+
+	return loanID, nil
+}
+
+// GetLoans 查询借币记录
+func (fs *FcoinService) GetLoans(query *LoanQuery) ([]LoanRecord, error) {
+	path := `/v2/broker/leveraged/loans`
+	data, err := fs.authorization("GET", path, query.values(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]LoanRecord, 0)
+	err = json.Unmarshal(data, &res)
+	if err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// GetLoan 查询单条借币记录
+func (fs *FcoinService) GetLoan(loanID string) (*LoanRecord, error) {
+	path := `/v2/broker/leveraged/loans/` + loanID
+	data, err := fs.authorization("GET", path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := new(LoanRecord)
+	err = json.Unmarshal(data, res)
+	if err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// Repay 归还指定借币记录 先还利息再还本金 返回还币记录 ID
+func (fs *FcoinService) Repay(loanID, currency, amount string) (string, error) {
+	values := url.Values{}
+	values.Add("currency", currency) // 还币币种
+	values.Add("amount", amount)     // 还币数量
+
+	path := `/v2/broker/leveraged/repayments/` + loanID
+	data, err := fs.authorization("POST", path, nil, values)
+	if err != nil {
+		return "", err
+	}
+
+	repaymentID := ""
+	err = json.Unmarshal(data, &repaymentID)
+	if err != nil {
+		return repaymentID, err
+	}
+
+	return repaymentID, nil
+}
+
+// GetRepayments 查询借币记录的还币记录
+func (fs *FcoinService) GetRepayments(loanID string) ([]RepaymentRecord, error) {
+	values := url.Values{}
+	values.Add("leveraged_loan_id", loanID)
+
+	path := `/v2/broker/leveraged/repayments`
+	data, err := fs.authorization("GET", path, values, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]RepaymentRecord, 0)
+	err = json.Unmarshal(data, &res)
+	if err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// CreateMarginOrder 在杠杆账户中下单 账户由 symbol 决定 返回订单ID
+func (fs *FcoinService) CreateMarginOrder(symbol, side, orderType, price, amount string) (string, error) {
+	return fs.createOrder(symbol, side, orderType, price, amount, AccountTypeMargin)
+}
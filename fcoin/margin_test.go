@@ -0,0 +1,35 @@
+package fcoin
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestLoanQueryValues(t *testing.T) {
+	v := (&LoanQuery{AccountType: "btcusdt", After: time.Unix(1, 0), Limit: 10}).values()
+	if v.Get("account_type") != "btcusdt" || v.Get("after") != "1000" || v.Get("limit") != "10" {
+		t.Fatal(v)
+	}
+	for _, key := range []string{"currency", "state", "before"} {
+		if _, ok := v[key]; ok {
+			t.Fatalf("%s should be omitted", key)
+		}
+	}
+	if v := (&OrderQuery{Symbol: "btcusdt", AccountType: AccountTypeMargin}).values(); v.Get("account_type") != "margin" {
+		t.Fatal(v)
+	}
+}
+
+func TestLeveragedAccountUnmarshal(t *testing.T) {
+	data := `{"open":true,"leveraged_account_type":"btcusdt","base":"btc","quote":"usdt",
+		"available_base_currency_amount":"0.1","available_quote_currency_loan_amount":"300",
+		"risk_rate":"2.5","state":"open"}`
+	a := new(LeveragedAccount)
+	if err := json.Unmarshal([]byte(data), a); err != nil {
+		t.Fatal(err)
+	}
+	if !a.Open || a.Base != "btc" || a.AvailableBaseCurrencyAmount != "0.1" || a.AvailableQuoteCurrencyLoanAmount != "300" {
+		t.Fatal(a)
+	}
+}
@@ -0,0 +1,187 @@
+package fcoin
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OrderState 订单状态
+type OrderState string
+
+// 订单状态
+const (
+	OrderSubmitted       OrderState = "submitted"        // 已提交
+	OrderPartialFilled   OrderState = "partial_filled"   // 部分成交
+	OrderPartialCanceled OrderState = "partial_canceled" // 部分成交已撤销
+	OrderFilled          OrderState = "filled"           // 完全成交
+	OrderCanceled        OrderState = "canceled"         // 已撤销
+	OrderPendingCancel   OrderState = "pending_cancel"   // 撤销已提交
+)
+
+// Valid 是否为已知状态
+func (s OrderState) Valid() bool {
+	switch s {
+	case OrderSubmitted, OrderPartialFilled, OrderPartialCanceled, OrderFilled, OrderCanceled, OrderPendingCancel:
+		return true
+	}
+	return false
+}
+
+// Terminal 订单已结束 不会再有成交
+func (s OrderState) Terminal() bool {
+	return s == OrderFilled || s == OrderCanceled || s == OrderPartialCanceled
+}
+
+// Active 订单仍在挂单中 撤销已提交但未完成的也算
+func (s OrderState) Active() bool {
+	return s == OrderSubmitted || s == OrderPartialFilled || s == OrderPendingCancel
+}
+
+// OrderQuery GetOrders 的查询条件
+// Before After 为订单创建时间的范围 零值表示不限制 Limit 为 0 时使用默认的 20 条
+// AccountType 为 AccountTypeMargin 时查询杠杆账户的订单
+type OrderQuery struct {
+	Symbol      string
+	States      []OrderState
+	Before      time.Time
+	After       time.Time
+	Limit       int
+	AccountType string
+}
+
+func (q *OrderQuery) values() url.Values {
+	states := make([]string, len(q.States))
+	for i, s := range q.States {
+		states[i] = string(s)
+	}
+	values := url.Values{}
+	values.Add("symbol", q.Symbol)                  // 交易对
+	values.Add("states", strings.Join(states, ",")) // 订单状态
+	if !q.Before.IsZero() {
+		values.Add("before", strconv.FormatInt(unixMilli(q.Before), 10)) // 查询某个时间之前的订单
+	}
+	if !q.After.IsZero() {
+		values.Add("after", strconv.FormatInt(unixMilli(q.After), 10)) // 查询某个时间之后的订单
+	}
+	if q.Limit > 0 {
+		values.Add("limit", strconv.Itoa(q.Limit)) // 每页的订单数量
+	}
+	if q.AccountType != "" {
+		values.Add("account_type", q.AccountType) // 账户类型
+	}
+	return values
+}
+
+// unixMilli 毫秒时间戳 零值时间为 0
+func unixMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano() / 1e6
+}
+
+// fromMilli 毫秒时间戳转时间 0 表示缺失 返回零值时间
+func fromMilli(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.Unix(ms/1e3, ms%1e3*1e6)
+}
+
+// OrderInformation 订单详情 接口中字符串形式的数值解析为 float64
+type OrderInformation struct {
+	ID            string
+	Symbol        string
+	Type          string
+	Side          string
+	Price         float64
+	Amount        float64
+	State         OrderState
+	ExecutedValue float64 // 已成交金额
+	FillFees      float64 // 手续费
+	FilledAmount  float64 // 已成交数量
+	CreatedAt     time.Time
+	Source        string
+}
+
+// orderInformationJSON 接口返回的原始格式
+type orderInformationJSON struct {
+	ID            string     `json:"id"`
+	Symbol        string     `json:"symbol"`
+	Type          string     `json:"type"`
+	Side          string     `json:"side"`
+	Price         string     `json:"price"`
+	Amount        string     `json:"amount"`
+	State         OrderState `json:"state"`
+	ExecutedValue string     `json:"executed_value"`
+	FillFees      string     `json:"fill_fees"`
+	FilledAmount  string     `json:"filled_amount"`
+	CreatedAt     int64      `json:"created_at"`
+	Source        string     `json:"source"`
+}
+
+// UnmarshalJSON 解析接口返回的订单
+func (o *OrderInformation) UnmarshalJSON(data []byte) error {
+	var raw orderInformationJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	res := OrderInformation{
+		ID:        raw.ID,
+		Symbol:    raw.Symbol,
+		Type:      raw.Type,
+		Side:      raw.Side,
+		State:     raw.State,
+		CreatedAt: fromMilli(raw.CreatedAt),
+		Source:    raw.Source,
+	}
+	fields := []struct {
+		text string
+		dst  *float64
+	}{
+		{raw.Price, &res.Price},
+		{raw.Amount, &res.Amount},
+		{raw.ExecutedValue, &res.ExecutedValue},
+		{raw.FillFees, &res.FillFees},
+		{raw.FilledAmount, &res.FilledAmount},
+	}
+	for _, f := range fields {
+		if f.text == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(f.text, 64)
+		if err != nil {
+			return err
+		}
+		*f.dst = v
+	}
+	*o = res
+	return nil
+}
+
+// MarshalJSON 编码成接口的原始格式
+func (o OrderInformation) MarshalJSON() ([]byte, error) {
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	return json.Marshal(orderInformationJSON{
+		ID:            o.ID,
+		Symbol:        o.Symbol,
+		Type:          o.Type,
+		Side:          o.Side,
+		Price:         f(o.Price),
+		Amount:        f(o.Amount),
+		State:         o.State,
+		ExecutedValue: f(o.ExecutedValue),
+		FillFees:      f(o.FillFees),
+		FilledAmount:  f(o.FilledAmount),
+		CreatedAt:     unixMilli(o.CreatedAt),
+		Source:        o.Source,
+	})
+}
+
+// Remaining 未成交数量
+func (o *OrderInformation) Remaining() float64 {
+	return o.Amount - o.FilledAmount
+}
@@ -0,0 +1,85 @@
+package fcoin
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestOrderInformationUnmarshal(t *testing.T) {
+	data := `{"id":"9d17a03b852e48c0b3920c7412867623","symbol":"btcusdt","type":"limit","side":"buy",
+		"price":"7000.5","amount":"0.5","state":"partial_filled","executed_value":"1400.1",
+		"fill_fees":"0.0002","filled_amount":"0.2","created_at":1531734611034,"source":"api"}`
+	o := new(OrderInformation)
+	if err := json.Unmarshal([]byte(data), o); err != nil {
+		t.Fatal(err)
+	}
+	if o.Price != 7000.5 || o.Amount != 0.5 || o.FilledAmount != 0.2 || o.FillFees != 0.0002 {
+		t.Fatal(o)
+	}
+	if !o.CreatedAt.Equal(time.Unix(1531734611, 34e6)) {
+		t.Fatal(o.CreatedAt)
+	}
+	if o.State != OrderPartialFilled || !o.State.Active() || o.State.Terminal() {
+		t.Fatal(o.State)
+	}
+
+	bs, err := json.Marshal(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again := new(OrderInformation)
+	if err := json.Unmarshal(bs, again); err != nil || *again != *o {
+		t.Fatal(string(bs), err)
+	}
+}
+
+func TestOrderInformationMissingTime(t *testing.T) {
+	o := new(OrderInformation)
+	if err := json.Unmarshal([]byte(`{"id":"1","price":"1","amount":"1"}`), o); err != nil {
+		t.Fatal(err)
+	}
+	if !o.CreatedAt.IsZero() {
+		t.Fatal(o.CreatedAt)
+	}
+	bs, err := json.Marshal(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again := new(OrderInformation)
+	if err := json.Unmarshal(bs, again); err != nil || !again.CreatedAt.IsZero() {
+		t.Fatal(string(bs), err)
+	}
+}
+
+func TestOrderStatePredicates(t *testing.T) {
+	for _, s := range []OrderState{OrderFilled, OrderCanceled, OrderPartialCanceled} {
+		if !s.Terminal() || s.Active() || !s.Valid() {
+			t.Error(s)
+		}
+	}
+	for _, s := range []OrderState{OrderSubmitted, OrderPartialFilled, OrderPendingCancel} {
+		if s.Terminal() || !s.Active() || !s.Valid() {
+			t.Error(s)
+		}
+	}
+	if OrderState("unknown").Valid() {
+		t.Error("unknown state is valid")
+	}
+}
+
+func TestOrderQueryValues(t *testing.T) {
+	q := &OrderQuery{
+		Symbol: "btcusdt",
+		States: []OrderState{OrderSubmitted, OrderPartialFilled},
+		Before: time.Unix(1531734611, 34e6),
+		Limit:  50,
+	}
+	v := q.values()
+	if v.Get("states") != "submitted,partial_filled" || v.Get("before") != "1531734611034" || v.Get("limit") != "50" {
+		t.Fatal(v)
+	}
+	if _, ok := v["after"]; ok {
+		t.Fatal("zero after should be omitted")
+	}
+}
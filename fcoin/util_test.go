@@ -3,6 +3,7 @@ package fcoin
 import (
 	"testing"
 	"net/url"
+	"time"
 )
 
 func TestSortedURL(t *testing.T) {
@@ -22,3 +23,19 @@ func TestSortedBody(t *testing.T) {
 	values.Add("symbol", "btcusdt")
 	t.Log(sortedBody(values))
 }
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+func TestFcoinServiceClock(t *testing.T) {
+	fs, _ := NewFcoinService("https://api.fcoin.com", "", "")
+	if d := time.Since(fs.now()); d < 0 || d > time.Second {
+		t.Fatalf("default clock off by %v", d)
+	}
+	want := time.Unix(1500000000, 123e6)
+	fs.SetClock(fixedClock(want))
+	if !fs.now().Equal(want) {
+		t.Fatal(fs.now())
+	}
+}
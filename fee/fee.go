@@ -0,0 +1,243 @@
+package fee
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go-exchange/fcoin"
+	"go-exchange/gateio"
+)
+
+// ErrNoRate 没有该交易所或交易对的费率
+var ErrNoRate = errors.New("no fee rate")
+
+// Rate 费率 小数表示 0.001 即千分之一
+// Currency 为空时手续费从收到的币种中扣除 买入扣基准货币 卖出扣计价货币
+// Currency 不为空时用该币种支付 如 bibox 的 bix 费率乘以 Discount
+type Rate struct {
+	Maker    float64 `json:"maker"`
+	Taker    float64 `json:"taker"`
+	Currency string  `json:"currency,omitempty"`
+	Discount float64 `json:"discount,omitempty"` // 0 表示没有折扣
+}
+
+// Effective 实际费率
+func (r Rate) Effective(maker bool) float64 {
+	v := r.Taker
+	if maker {
+		v = r.Maker
+	}
+	if r.Currency != "" && r.Discount > 0 {
+		v *= r.Discount
+	}
+	return v
+}
+
+// PriceFunc 1 单位 currency 折合 quote 的价格 用于换算第三种币支付的手续费
+type PriceFunc func(currency, quote string) (float64, error)
+
+// Model 各交易所 各交易对的费率 交易对统一为小写的 base/quote
+type Model struct {
+	Price PriceFunc // 可以为 nil
+
+	mu       sync.RWMutex
+	defaults map[string]Rate
+	pairs    map[string]map[string]Rate
+}
+
+// NewModel 新建费率模型
+func NewModel() *Model {
+	return &Model{
+		defaults: make(map[string]Rate),
+		pairs:    make(map[string]map[string]Rate),
+	}
+}
+
+func pairKey(base, quote string) string {
+	return strings.ToLower(base) + "/" + strings.ToLower(quote)
+}
+
+// SetDefault 交易所的默认费率
+func (m *Model) SetDefault(venue string, r Rate) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.defaults[venue] = r
+}
+
+// Set 单个交易对的费率 优先于默认费率
+func (m *Model) Set(venue, base, quote string, r Rate) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.pairs[venue] == nil {
+		m.pairs[venue] = make(map[string]Rate)
+	}
+	m.pairs[venue][pairKey(base, quote)] = r
+}
+
+// Rate 查询费率 没有交易对费率时使用默认费率
+func (m *Model) Rate(venue, base, quote string) (Rate, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if r, ok := m.pairs[venue][pairKey(base, quote)]; ok {
+		return r, nil
+	}
+	if r, ok := m.defaults[venue]; ok {
+		return r, nil
+	}
+	return Rate{}, ErrNoRate
+}
+
+// LoadGate 从 api2 MarketInfo 载入费率 fee 为百分比 gate 不区分 maker taker
+func (m *Model) LoadGate(venue string, info *gateio.MarketInfoResult) {
+	for _, pairs := range info.Pairs {
+		for pair, p := range pairs {
+			parts := strings.SplitN(pair, "_", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			v := p.Fee / 100
+			m.Set(venue, parts[0], parts[1], Rate{Maker: v, Taker: v})
+		}
+	}
+}
+
+// LoadGateV4 从 v4 ListCurrencyPairs 载入费率 fee 为百分比
+func (m *Model) LoadGateV4(venue string, pairs []gateio.V4CurrencyPair) error {
+	for _, p := range pairs {
+		v, err := strconv.ParseFloat(p.Fee, 64)
+		if err != nil {
+			return fmt.Errorf("%s fee %q: %v", p.ID, p.Fee, err)
+		}
+		m.Set(venue, p.Base, p.Quote, Rate{Maker: v / 100, Taker: v / 100})
+	}
+	return nil
+}
+
+// LoadFcoin fcoin 没有费率接口 对所有交易对设置同一费率
+func (m *Model) LoadFcoin(venue string, symbols []fcoin.Symbol, r Rate) {
+	for _, s := range symbols {
+		m.Set(venue, s.BaseCurrency, s.QuoteCurrency, r)
+	}
+}
+
+// Config 配置文件格式
+//
+//	{
+//	  "bibox": {"default": {"maker": 0.001, "taker": 0.001, "currency": "bix", "discount": 0.5}},
+//	  "fcoin": {"default": {"maker": 0.001, "taker": 0.001}, "pairs": {"ft/usdt": {"maker": 0, "taker": 0.001}}}
+//	}
+type Config map[string]struct {
+	Default *Rate           `json:"default"`
+	Pairs   map[string]Rate `json:"pairs"`
+}
+
+// LoadConfig 从 JSON 配置载入费率
+func (m *Model) LoadConfig(r io.Reader) error {
+	var config Config
+	if err := json.NewDecoder(r).Decode(&config); err != nil {
+		return err
+	}
+	for venue, c := range config {
+		if c.Default != nil {
+			m.SetDefault(venue, *c.Default)
+		}
+		for pair, rate := range c.Pairs {
+			parts := strings.SplitN(pair, "/", 2)
+			if len(parts) != 2 {
+				return fmt.Errorf("%s pair %q should be base/quote", venue, pair)
+			}
+			m.Set(venue, parts[0], parts[1], rate)
+		}
+	}
+	return nil
+}
+
+// Estimate 下单前估算的手续费
+type Estimate struct {
+	Rate     float64 // 实际费率
+	Fee      float64 // 以 Currency 计的手续费
+	Currency string
+	Value    float64 // 折合计价货币
+}
+
+// Estimate 估算手续费 side 为 buy 或 sell amount 为基准货币数量
+func (m *Model) Estimate(venue, base, quote, side string, price, amount float64, maker bool) (Estimate, error) {
+	r, err := m.Rate(venue, base, quote)
+	if err != nil {
+		return Estimate{}, err
+	}
+	base, quote = strings.ToLower(base), strings.ToLower(quote)
+	e := Estimate{Rate: r.Effective(maker)}
+	e.Value = price * amount * e.Rate
+	switch {
+	case r.Currency != "":
+		e.Currency = strings.ToLower(r.Currency)
+		p, err := m.price(e.Currency, base, quote, price)
+		if err != nil {
+			return e, err
+		}
+		e.Fee = e.Value / p
+	case side == "buy":
+		e.Currency, e.Fee = base, amount*e.Rate
+	case side == "sell":
+		e.Currency, e.Fee = quote, e.Value
+	default:
+		return e, fmt.Errorf("unknown side %q", side)
+	}
+	return e, nil
+}
+
+// price 1 单位 currency 折合 quote 基准和计价货币按成交价
+func (m *Model) price(currency, base, quote string, price float64) (float64, error) {
+	switch currency {
+	case quote:
+		return 1, nil
+	case base:
+		return price, nil
+	}
+	if m.Price == nil {
+		return 0, fmt.Errorf("no price for fee currency %s", currency)
+	}
+	p, err := m.Price(currency, quote)
+	if err != nil {
+		return 0, err
+	}
+	if p <= 0 {
+		return 0, fmt.Errorf("invalid %s price %v", currency, p)
+	}
+	return p, nil
+}
+
+// NetAmount 扣除手续费后实际收到的数量 买入为基准货币 卖出为计价货币
+// 用其他币种支付手续费时不扣除
+func (e Estimate) NetAmount(base, quote, side string, price, amount float64) float64 {
+	switch side {
+	case "buy":
+		if e.Currency == strings.ToLower(base) {
+			return amount - e.Fee
+		}
+		return amount
+	default:
+		if e.Currency == strings.ToLower(quote) {
+			return price*amount - e.Fee
+		}
+		return price * amount
+	}
+}
+
+// tolerance 实际费率与模型费率的允许误差 交易所对手续费取整
+const tolerance = 0.05
+
+// closeTo 相对误差在 tolerance 以内
+func closeTo(actual, expected float64) bool {
+	if expected == 0 {
+		return actual < 1e-12
+	}
+	return math.Abs(actual-expected) <= expected*tolerance
+}
@@ -0,0 +1,86 @@
+package fee
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"go-exchange/gateio"
+	"go-exchange/venue"
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+const config = `{
+	"bibox": {"default": {"maker": 0.001, "taker": 0.002, "currency": "BIX", "discount": 0.5}},
+	"fcoin": {"default": {"maker": 0.001, "taker": 0.001}, "pairs": {"FT/USDT": {"maker": 0, "taker": 0.001}}}
+}`
+
+func TestEstimate(t *testing.T) {
+	m := NewModel()
+	if err := m.LoadConfig(strings.NewReader(config)); err != nil {
+		t.Fatal(err)
+	}
+	m.Price = func(currency, quote string) (float64, error) { return 0.05, nil }
+
+	e, err := m.Estimate("fcoin", "eth", "usdt", "buy", 200, 2, false)
+	if err != nil || e.Currency != "eth" || !near(e.Fee, 0.002) || !near(e.Value, 0.4) {
+		t.Fatal(e, err)
+	}
+	if net := e.NetAmount("eth", "usdt", "buy", 200, 2); !near(net, 1.998) {
+		t.Fatal(net)
+	}
+	e, err = m.Estimate("fcoin", "ft", "usdt", "sell", 0.1, 100, true)
+	if err != nil || e.Currency != "usdt" || e.Fee != 0 {
+		t.Fatal(e, err)
+	}
+	// bix 支付 taker 0.2% 五折 400 * 0.001 = 0.4 usdt = 8 bix
+	e, err = m.Estimate("bibox", "eth", "usdt", "sell", 200, 2, false)
+	if err != nil || e.Currency != "bix" || !near(e.Fee, 8) || !near(e.Rate, 0.001) {
+		t.Fatal(e, err)
+	}
+	if _, err := m.Estimate("gate", "eth", "usdt", "buy", 1, 1, false); err != ErrNoRate {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadGate(t *testing.T) {
+	info := new(gateio.MarketInfoResult)
+	data := `{"result":"true","pairs":[{"eth_btc":{"decimal_places":6,"min_amount":0.0001,"fee":0.2}}]}`
+	if err := json.Unmarshal([]byte(data), info); err != nil {
+		t.Fatal(err)
+	}
+	m := NewModel()
+	m.LoadGate("gate", info)
+	r, err := m.Rate("gate", "ETH", "BTC")
+	if err != nil || !near(r.Taker, 0.002) || !near(r.Maker, 0.002) {
+		t.Fatal(r, err)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	m := NewModel()
+	m.SetDefault("fcoin", Rate{Maker: 0.0005, Taker: 0.001})
+	fills := []venue.Fill{
+		{Venue: "fcoin", Base: "eth", Quote: "usdt", Side: "buy", Price: 200, Amount: 1, Fee: 0.001, FeeCurrency: "eth"},
+		{Venue: "fcoin", Base: "eth", Quote: "usdt", Side: "sell", Price: 200, Amount: 1, Fee: 0.1, FeeCurrency: "usdt"},
+		{Venue: "fcoin", Base: "eth", Quote: "usdt", Side: "sell", Price: 200, Amount: 1, Fee: 0.3, FeeCurrency: "usdt"},
+		{Venue: "gate", Base: "eth", Quote: "usdt", Side: "sell", Price: 200, Amount: 1},
+	}
+	res, sum := m.Reconcile(fills)
+	if !res[0].Match || res[0].Maker || !near(res[0].ActualValue, 0.2) {
+		t.Fatal(res[0])
+	}
+	if !res[1].Match || !res[1].Maker {
+		t.Fatal(res[1])
+	}
+	if res[2].Match || res[3].Err != ErrNoRate {
+		t.Fatal(res[2], res[3])
+	}
+	if sum.Count != 4 || sum.Mismatched != 1 || sum.Failed != 1 || !near(sum.ActualValue, 0.6) || !near(sum.ExpectedValue, 0.5) {
+		t.Fatal(sum)
+	}
+}
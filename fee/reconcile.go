@@ -0,0 +1,88 @@
+package fee
+
+import (
+	"strings"
+
+	"go-exchange/venue"
+)
+
+// Reconciliation 一笔成交的实际手续费与模型的比较
+type Reconciliation struct {
+	Fill        venue.Fill
+	ActualRate  float64 // 实际手续费价值 / 成交金额
+	ActualValue float64 // 实际手续费折合计价货币
+	Maker       bool    // 实际费率更接近 maker 费率
+	Expected    float64 // 对应的模型费率
+	Match       bool    // 实际费率与模型费率的相对误差在 5% 以内
+	Err         error   // 没有费率或无法换算手续费币种
+}
+
+// Summary 对账汇总 金额都是各成交计价货币的简单相加 只适合同一计价货币
+type Summary struct {
+	Count         int
+	Mismatched    int
+	Failed        int
+	ActualValue   float64
+	ExpectedValue float64
+}
+
+// Reconcile 逐笔比较成交的实际手续费与模型费率
+// 成交不带 maker taker 信息 按实际费率更接近哪一个判断
+func (m *Model) Reconcile(fills []venue.Fill) ([]Reconciliation, Summary) {
+	res := make([]Reconciliation, 0, len(fills))
+	var sum Summary
+	for _, f := range fills {
+		rc := m.reconcile(f)
+		sum.Count++
+		switch {
+		case rc.Err != nil:
+			sum.Failed++
+		case !rc.Match:
+			sum.Mismatched++
+		}
+		if rc.Err == nil {
+			notional := f.Price * f.Amount
+			sum.ActualValue += rc.ActualValue
+			sum.ExpectedValue += notional * rc.Expected
+		}
+		res = append(res, rc)
+	}
+	return res, sum
+}
+
+func (m *Model) reconcile(f venue.Fill) Reconciliation {
+	rc := Reconciliation{Fill: f}
+	r, err := m.Rate(f.Venue, f.Base, f.Quote)
+	if err != nil {
+		rc.Err = err
+		return rc
+	}
+	notional := f.Price * f.Amount
+	if notional <= 0 {
+		rc.Match = f.Fee == 0
+		return rc
+	}
+	p, err := m.price(strings.ToLower(f.FeeCurrency), strings.ToLower(f.Base), strings.ToLower(f.Quote), f.Price)
+	if err != nil {
+		rc.Err = err
+		return rc
+	}
+	rc.ActualValue = f.Fee * p
+	rc.ActualRate = rc.ActualValue / notional
+
+	maker, taker := r.Effective(true), r.Effective(false)
+	rc.Maker = maker != taker && distance(rc.ActualRate, maker) < distance(rc.ActualRate, taker)
+	rc.Expected = taker
+	if rc.Maker {
+		rc.Expected = maker
+	}
+	rc.Match = closeTo(rc.ActualRate, rc.Expected)
+	return rc
+}
+
+func distance(a, b float64) float64 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
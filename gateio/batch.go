@@ -0,0 +1,87 @@
+package gateio
+
+import (
+	"fmt"
+	"time"
+
+	"go-exchange/batch"
+)
+
+// DefaultBatchOptions 批量请求的默认并发与间隔
+var DefaultBatchOptions = batch.Options{Concurrency: 4, Interval: 100 * time.Millisecond}
+
+// BatchOrder 批量下单中的一个订单 Side 为 buy 或 sell
+type BatchOrder struct {
+	Side string
+	OrderRequest
+}
+
+// BatchOrderResult 批量下单中一个订单的结果
+type BatchOrderResult struct {
+	Result *OrderResult
+	Err    error
+}
+
+// CancelRequest 批量撤单中的一个订单
+type CancelRequest struct {
+	OrderNumber  string
+	CurrencyPair string
+}
+
+// PlaceOrders 并发下单 结果与 orders 顺序一致 返回第一个错误
+// opts 为 nil 时使用 DefaultBatchOptions
+func (s *Service) PlaceOrders(orders []BatchOrder, opts *batch.Options) ([]BatchOrderResult, error) {
+	res := make([]BatchOrderResult, len(orders))
+	errs := batch.Run(len(orders), batchOptions(opts), func(i int) error {
+		o := orders[i]
+		var (
+			r   *OrderResult
+			err error
+		)
+		switch o.Side {
+		case "buy":
+			r, err = s.Buy(&o.OrderRequest)
+		case "sell":
+			r, err = s.Sell(&o.OrderRequest)
+		default:
+			err = fmt.Errorf("unknown side %q", o.Side)
+		}
+		res[i].Result = r
+		return err
+	})
+	for i, err := range errs {
+		res[i].Err = err
+	}
+	return res, batch.First(errs)
+}
+
+// BatchCancelResult 批量撤单中一个订单的结果
+type BatchCancelResult struct {
+	OrderNumber  string
+	CurrencyPair string
+	Success      bool
+	Err          error
+}
+
+// CancelOrders 并发撤单 结果与 reqs 顺序一致 返回第一个错误
+// opts 为 nil 时使用 DefaultBatchOptions
+func (s *Service) CancelOrders(reqs []CancelRequest, opts *batch.Options) ([]BatchCancelResult, error) {
+	res := make([]BatchCancelResult, len(reqs))
+	errs := batch.Run(len(reqs), batchOptions(opts), func(i int) error {
+		return s.CancelOrder(reqs[i].OrderNumber, reqs[i].CurrencyPair)
+	})
+	for i, err := range errs {
+		res[i].OrderNumber = reqs[i].OrderNumber
+		res[i].CurrencyPair = reqs[i].CurrencyPair
+		res[i].Success = err == nil
+		res[i].Err = err
+	}
+	return res, batch.First(errs)
+}
+
+func batchOptions(opts *batch.Options) batch.Options {
+	if opts == nil {
+		return DefaultBatchOptions
+	}
+	return *opts
+}
@@ -0,0 +1,95 @@
+package gateio
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"go-exchange/batch"
+)
+
+// rewriteTransport 把请求转到测试服务器
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (t rewriteTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r.URL.Scheme, r.URL.Host = t.target.Scheme, t.target.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// useServer 让 http.DefaultClient 请求 handler 返回恢复函数
+func useServer(handler http.HandlerFunc) func() {
+	server := httptest.NewServer(handler)
+	target, _ := url.Parse(server.URL)
+	old := http.DefaultClient.Transport
+	http.DefaultClient.Transport = rewriteTransport{target: target}
+	return func() {
+		http.DefaultClient.Transport = old
+		server.Close()
+	}
+}
+
+func TestPlaceOrders(t *testing.T) {
+	defer useServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("rate") == "0" {
+			fmt.Fprint(w, `{"result":"false","code":8,"message":"Error: invalid rate"}`)
+			return
+		}
+		number := 1
+		if r.URL.Path == "/api2/1/private/sell" {
+			number = 2
+		}
+		fmt.Fprintf(w, `{"result":"true","orderNumber":%d,"rate":%q}`, number, r.FormValue("rate"))
+	})()
+
+	s := NewService("key", "secret")
+	orders := []BatchOrder{
+		{Side: "buy", OrderRequest: OrderRequest{CurrencyPair: "eth_usdt", Rate: "1", Amount: "1"}},
+		{Side: "sell", OrderRequest: OrderRequest{CurrencyPair: "eth_usdt", Rate: "0", Amount: "1"}},
+		{Side: "sell", OrderRequest: OrderRequest{CurrencyPair: "eth_usdt", Rate: "3", Amount: "1"}},
+		{Side: "hold", OrderRequest: OrderRequest{CurrencyPair: "eth_usdt", Rate: "4", Amount: "1"}},
+	}
+	res, err := s.PlaceOrders(orders, &batch.Options{Concurrency: 4})
+	if e, ok := err.(*Error); !ok || e.Code != 8 {
+		t.Fatal("want first error", err)
+	}
+	if res[0].Err != nil || res[0].Result.OrderNumber != "1" || res[2].Result.OrderNumber != "2" || res[2].Result.Rate != 3 {
+		t.Fatal(res)
+	}
+	if res[1].Err == nil || res[3].Err == nil {
+		t.Fatal(res)
+	}
+}
+
+func TestCancelOrders(t *testing.T) {
+	defer useServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api2/1/private/cancelOrder" {
+			t.Errorf("path %s", r.URL.Path)
+		}
+		if r.FormValue("orderNumber") == "2" {
+			fmt.Fprint(w, `{"result":"false","code":17,"message":"Error: order not found"}`)
+			return
+		}
+		fmt.Fprint(w, `{"result":"true","message":"Success"}`)
+	})()
+
+	s := NewService("key", "secret")
+	reqs := []CancelRequest{
+		{OrderNumber: "1", CurrencyPair: "eth_usdt"},
+		{OrderNumber: "2", CurrencyPair: "eth_usdt"},
+		{OrderNumber: "3", CurrencyPair: "btc_usdt"},
+	}
+	res, err := s.CancelOrders(reqs, &batch.Options{Concurrency: 2})
+	if err == nil {
+		t.Fatal("want first error")
+	}
+	if len(res) != 3 || res[1].OrderNumber != "2" || res[1].Success || res[1].Err == nil {
+		t.Fatal(res)
+	}
+	if !res[0].Success || res[2].OrderNumber != "3" || res[2].CurrencyPair != "btc_usdt" || !res[2].Success {
+		t.Fatal(res)
+	}
+}
@@ -28,14 +28,40 @@ func NewService(apiKey, secret string) *Service {
 	}
 }
 
+// Error gate 接口返回 result 为 false 时的错误信息
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("gate error code %d msg %s", e.Code, e.Message)
+}
+
+// checkResult 返回体是对象且 result 为 false 时返回 *Error
+// result 有时是字符串 "false" 有时是布尔值 数组等其他返回体不检查
+// code 或 message 类型不符时 Message 为原始返回体
+func checkResult(bs []byte) error {
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(bs, &fields); err != nil {
+		return nil
+	}
+	if r := string(fields["result"]); r != `"false"` && r != "false" {
+		return nil
+	}
+	e := new(Error)
+	if err := json.Unmarshal(bs, e); err != nil {
+		return &Error{Message: string(bs)}
+	}
+	return e
+}
+
 func (s *Service) requestJSON(method, path string, values url.Values, target interface{}) error {
-	resp, err := s.doHTTP(method, path, values)
+	bs, err := s.requestBlob(method, path, values)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
-	err = json.NewDecoder(resp.Body).Decode(target)
-	return err
+	return json.Unmarshal(bs, target)
 }
 
 func (s *Service) requestBlob(method, path string, values url.Values) ([]byte, error) {
@@ -44,7 +70,14 @@ func (s *Service) requestBlob(method, path string, values url.Values) ([]byte, e
 		return nil, err
 	}
 	defer resp.Body.Close()
-	return ioutil.ReadAll(resp.Body)
+	bs, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResult(bs); err != nil {
+		return nil, err
+	}
+	return bs, nil
 }
 
 func (s *Service) doHTTP(method, path string, values url.Values) (*http.Response, error) {
@@ -110,26 +143,50 @@ type MarketInfoResult struct {
 	} `json:"pairs"`
 }
 
+// MarketListItem 交易市场详细行情
+type MarketListItem struct {
+	No          int    `json:"no"`
+	Symbol      string `json:"symbol"`
+	Name        string `json:"name"`
+	NameEn      string `json:"name_en"`
+	NameCn      string `json:"name_cn"`
+	Pair        string `json:"pair"`
+	Rate        Number `json:"rate"`         // 最新价
+	VolA        Number `json:"vol_a"`        // 基准货币成交量
+	VolB        Number `json:"vol_b"`        // 计价货币成交量
+	CurrA       string `json:"curr_a"`       // 基准货币
+	CurrB       string `json:"curr_b"`       // 计价货币
+	CurrSuffix  string `json:"curr_suffix"`  // 计价货币后缀
+	RatePercent Number `json:"rate_percent"` // 涨跌幅 百分比
+	Trend       string `json:"trend"`        // up down
+	Supply      Number `json:"supply"`       // 流通量
+	MarketCap   Number `json:"marketcap"`    // 总市值
+}
+
 // MarketList 交易市场详细行情 API
-func (s *Service) MarketList() (string, error) {
+func (s *Service) MarketList() ([]MarketListItem, error) {
 	path := "/api2/1/marketlist"
-	bs, err := s.requestBlob("GET", path, nil)
+	res := new(struct {
+		Result string           `json:"result"`
+		Data   []MarketListItem `json:"data"`
+	})
+	err := s.requestJSON("GET", path, nil, res)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return string(bs), nil
+	return res.Data, nil
 }
 
 type Ticker struct {
 	Result        string `json:"result"`
-	Last          string `json:"last"`
-	LowestAsk     string `json:"lowestAsk"`
-	HighestBid    string `json:"highestBid"`
-	PercentChange string `json:"percentChange"`
-	BaseVolume    string `json:"baseVolume"`
-	QuoteVolume   string `json:"quoteVolume"`
-	High24Hr      string `json:"high24hr"`
-	Low24Hr       string `json:"low24hr"`
+	Last          Number `json:"last"`
+	LowestAsk     Number `json:"lowestAsk"`
+	HighestBid    Number `json:"highestBid"`
+	PercentChange Number `json:"percentChange"`
+	BaseVolume    Number `json:"baseVolume"`
+	QuoteVolume   Number `json:"quoteVolume"`
+	High24Hr      Number `json:"high24hr"`
+	Low24Hr       Number `json:"low24hr"`
 	Elapsed       string `json:"elapsed"`
 }
 
@@ -156,13 +213,12 @@ func (s *Service) Ticker(ticker string) (*Ticker, error) {
 	return res, nil
 }
 
-// OrderBook 交易深度
+// OrderBook 交易深度 Asks 按价格从低到高 Bids 按价格从高到低
 type OrderBook struct {
-	Result  string `json:"result"`
-	Elapsed string `json:"elapsed"`
-	// 深度里面各种类型混着放 法克
-	Asks [][]interface{} `json:"asks"`
-	Bids [][]interface{} `json:"bids"`
+	Result  string       `json:"result"`
+	Elapsed string       `json:"elapsed"`
+	Asks    []PriceLevel `json:"asks"`
+	Bids    []PriceLevel `json:"bids"`
 }
 
 // OrderBooks 返回系统支持的所有交易对的市场深度（委托挂单），其中 asks 是委卖单, bids 是委买单
@@ -173,6 +229,11 @@ func (s *Service) OrderBooks() (map[string]OrderBook, error) {
 	if err != nil {
 		return nil, err
 	}
+	for pair, ob := range ts {
+		if err := sortLevels(ob.Asks, ob.Bids); err != nil {
+			return nil, fmt.Errorf("%s: %v", pair, err)
+		}
+	}
 	return ts, nil
 }
 
@@ -185,6 +246,9 @@ func (s *Service) OrderBook(pair string) (*OrderBook, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := sortLevels(res.Asks, res.Bids); err != nil {
+		return nil, err
+	}
 	return res, nil
 }
 
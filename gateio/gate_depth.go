@@ -0,0 +1,54 @@
+package gateio
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// PriceLevel 深度中的一档 接口返回 [价格, 数量] 两个位置都可能是数字或字符串
+type PriceLevel struct {
+	Price  float64
+	Amount float64
+}
+
+// UnmarshalJSON 解析 [价格, 数量]
+func (l *PriceLevel) UnmarshalJSON(data []byte) error {
+	var fields []json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	if len(fields) != 2 {
+		return fmt.Errorf("price level want 2 fields got %d", len(fields))
+	}
+	price, err := parseNumber(fields[0])
+	if err != nil {
+		return fmt.Errorf("price level price: %v", err)
+	}
+	amount, err := parseNumber(fields[1])
+	if err != nil {
+		return fmt.Errorf("price level amount: %v", err)
+	}
+	l.Price, l.Amount = price, amount
+	return nil
+}
+
+// sortLevels asks 按价格从低到高 bids 按价格从高到低 并检查每档价格数量以及是否交叉
+func sortLevels(asks, bids []PriceLevel) error {
+	sort.SliceStable(asks, func(i, j int) bool { return asks[i].Price < asks[j].Price })
+	sort.SliceStable(bids, func(i, j int) bool { return bids[i].Price > bids[j].Price })
+	for _, l := range asks {
+		if l.Price <= 0 || l.Amount < 0 {
+			return fmt.Errorf("invalid ask level price %v amount %v", l.Price, l.Amount)
+		}
+	}
+	for _, l := range bids {
+		if l.Price <= 0 || l.Amount < 0 {
+			return fmt.Errorf("invalid bid level price %v amount %v", l.Price, l.Amount)
+		}
+	}
+	if len(asks) > 0 && len(bids) > 0 && bids[0].Price >= asks[0].Price {
+		return fmt.Errorf("crossed book best bid %v best ask %v", bids[0].Price, asks[0].Price)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package gateio
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOrderBookLevels(t *testing.T) {
+	data := `{"result":"true","asks":[[0.31,"12"],["0.3",5.5]],"bids":[["0.28","3"],[0.29,1]]}`
+	ob := new(OrderBook)
+	if err := json.Unmarshal([]byte(data), ob); err != nil {
+		t.Fatal(err)
+	}
+	if err := sortLevels(ob.Asks, ob.Bids); err != nil {
+		t.Fatal(err)
+	}
+	if ob.Asks[0] != (PriceLevel{0.3, 5.5}) || ob.Asks[1] != (PriceLevel{0.31, 12}) {
+		t.Fatal(ob.Asks)
+	}
+	if ob.Bids[0] != (PriceLevel{0.29, 1}) || ob.Bids[1] != (PriceLevel{0.28, 3}) {
+		t.Fatal(ob.Bids)
+	}
+}
+
+func TestOrderBookLevelsInvalid(t *testing.T) {
+	var l PriceLevel
+	if err := json.Unmarshal([]byte(`["0.3"]`), &l); err == nil {
+		t.Fatal("single field level should fail")
+	}
+	if err := json.Unmarshal([]byte(`["abc","1"]`), &l); err == nil {
+		t.Fatal("non numeric price should fail")
+	}
+	crossed := sortLevels([]PriceLevel{{0.3, 1}}, []PriceLevel{{0.31, 1}})
+	if crossed == nil {
+		t.Fatal("crossed book should fail")
+	}
+	if err := sortLevels([]PriceLevel{{0, 1}}, nil); err == nil {
+		t.Fatal("zero price should fail")
+	}
+}
@@ -0,0 +1,238 @@
+package gateio
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// requestPrivate 调用私有接口 target 为 nil 时只检查 result
+func (s *Service) requestPrivate(path string, values url.Values, target interface{}) error {
+	if target == nil {
+		_, err := s.requestBlob("POST", path, values)
+		return err
+	}
+	return s.requestJSON("POST", path, values, target)
+}
+
+// OrderRequest 下单参数
+type OrderRequest struct {
+	CurrencyPair string // 交易对 eth_btc
+	Rate         string // 价格
+	Amount       string // 交易量
+}
+
+func (r *OrderRequest) values() url.Values {
+	values := url.Values{}
+	values.Set("currencyPair", r.CurrencyPair)
+	values.Set("rate", r.Rate)
+	values.Set("amount", r.Amount)
+	return values
+}
+
+// OrderResult 下单结果
+type OrderResult struct {
+	Result       string      `json:"result"`
+	OrderNumber  json.Number `json:"orderNumber"`
+	Rate         Number      `json:"rate"`
+	LeftAmount   Number      `json:"leftAmount"`
+	FilledAmount Number      `json:"filledAmount"`
+	FilledRate   Number      `json:"filledRate"`
+	Message      string      `json:"message"`
+}
+
+// Buy 下单买入
+func (s *Service) Buy(req *OrderRequest) (*OrderResult, error) {
+	path := "/api2/1/private/buy"
+	res := new(OrderResult)
+	err := s.requestPrivate(path, req.values(), res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Sell 下单卖出
+func (s *Service) Sell(req *OrderRequest) (*OrderResult, error) {
+	path := "/api2/1/private/sell"
+	res := new(OrderResult)
+	err := s.requestPrivate(path, req.values(), res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// CancelOrder 取消订单
+func (s *Service) CancelOrder(orderNumber, currencyPair string) error {
+	path := "/api2/1/private/cancelOrder"
+	values := url.Values{}
+	values.Set("orderNumber", orderNumber)
+	values.Set("currencyPair", currencyPair)
+	return s.requestPrivate(path, values, nil)
+}
+
+// CancelAllOrders 的订单类型
+const (
+	CancelSell = "0"  // 卖单
+	CancelBuy  = "1"  // 买单
+	CancelAll  = "-1" // 全部
+)
+
+// CancelAllOrders 取消交易对下所有订单 types 取 CancelSell CancelBuy CancelAll
+func (s *Service) CancelAllOrders(types, currencyPair string) error {
+	path := "/api2/1/private/cancelAllOrders"
+	values := url.Values{}
+	values.Set("type", types)
+	values.Set("currencyPair", currencyPair)
+	return s.requestPrivate(path, values, nil)
+}
+
+// Order 订单详情
+type Order struct {
+	OrderNumber   json.Number `json:"orderNumber"`
+	Status        string      `json:"status"`
+	CurrencyPair  string      `json:"currencyPair"`
+	Type          string      `json:"type"`
+	Rate          Number      `json:"rate"`
+	Amount        Number      `json:"amount"`
+	Total         Number      `json:"total"`
+	InitialRate   Number      `json:"initialRate"`
+	InitialAmount Number      `json:"initialAmount"`
+	FilledRate    Number      `json:"filledRate"`
+	FilledAmount  Number      `json:"filledAmount"`
+	FeePercentage Number      `json:"feePercentage"`
+	FeeValue      Number      `json:"feeValue"`
+	FeeCurrency   string      `json:"feeCurrency"`
+	Fee           string      `json:"fee"`
+	Timestamp     Number      `json:"timestamp"`
+}
+
+// GetOrder 获取订单状态
+func (s *Service) GetOrder(orderNumber, currencyPair string) (*Order, error) {
+	path := "/api2/1/private/getOrder"
+	values := url.Values{}
+	values.Set("orderNumber", orderNumber)
+	values.Set("currencyPair", currencyPair)
+	res := new(struct {
+		Order *Order `json:"order"`
+	})
+	err := s.requestPrivate(path, values, res)
+	if err != nil {
+		return nil, err
+	}
+	if res.Order == nil {
+		return nil, fmt.Errorf("order %s not found", orderNumber)
+	}
+	return res.Order, nil
+}
+
+// OpenOrders 获取我的当前挂单列表
+func (s *Service) OpenOrders() ([]Order, error) {
+	path := "/api2/1/private/openOrders"
+	res := new(struct {
+		Orders []Order `json:"orders"`
+	})
+	err := s.requestPrivate(path, url.Values{}, res)
+	if err != nil {
+		return nil, err
+	}
+	return res.Orders, nil
+}
+
+// MyTrade 我的成交记录
+type MyTrade struct {
+	TradeID     json.Number `json:"tradeID"`
+	OrderNumber json.Number `json:"orderNumber"`
+	Pair        string      `json:"pair"`
+	Type        string      `json:"type"`
+	Rate        Number      `json:"rate"`
+	Amount      Number      `json:"amount"`
+	Total       Number      `json:"total"`
+	Date        string      `json:"date"`
+	TimeUnix    Number      `json:"time_unix"`
+	Role        string      `json:"role"`
+	Fee         Number      `json:"fee"`
+	FeeCoin     string      `json:"fee_coin"`
+}
+
+// MyTradeHistory 获取我的24小时内成交记录 orderNumber 为空时返回交易对下全部成交
+func (s *Service) MyTradeHistory(currencyPair, orderNumber string) ([]MyTrade, error) {
+	path := "/api2/1/private/tradeHistory"
+	values := url.Values{}
+	values.Set("currencyPair", currencyPair)
+	if orderNumber != "" {
+		values.Set("orderNumber", orderNumber)
+	}
+	res := new(struct {
+		Trades []MyTrade `json:"trades"`
+	})
+	err := s.requestPrivate(path, values, res)
+	if err != nil {
+		return nil, err
+	}
+	return res.Trades, nil
+}
+
+// WithdrawRequest 提现参数
+type WithdrawRequest struct {
+	Currency string // 币种
+	Amount   string // 数量
+	Address  string // 提现地址
+}
+
+// Withdraw 提现
+func (s *Service) Withdraw(req *WithdrawRequest) error {
+	path := "/api2/1/private/withdraw"
+	values := url.Values{}
+	values.Set("currency", req.Currency)
+	values.Set("amount", req.Amount)
+	values.Set("address", req.Address)
+	return s.requestPrivate(path, values, nil)
+}
+
+// DepositAddress 获取充值地址
+func (s *Service) DepositAddress(currency string) (string, error) {
+	path := "/api2/1/private/depositAddress"
+	values := url.Values{}
+	values.Set("currency", currency)
+	res := new(struct {
+		Addr string `json:"addr"`
+	})
+	err := s.requestPrivate(path, values, res)
+	if err != nil {
+		return "", err
+	}
+	return res.Addr, nil
+}
+
+// Transfer 充值提现记录
+type Transfer struct {
+	ID        string `json:"id"`
+	Currency  string `json:"currency"`
+	Address   string `json:"address"`
+	Amount    Number `json:"amount"`
+	Txid      string `json:"txid"`
+	Timestamp Number `json:"timestamp"`
+	Status    string `json:"status"`
+}
+
+// DepositsWithdrawalsResult 充值提现历史
+type DepositsWithdrawalsResult struct {
+	Deposits  []Transfer `json:"deposits"`
+	Withdraws []Transfer `json:"withdraws"`
+}
+
+// DepositsWithdrawals 获取充值提现历史 start end 为 unix 秒
+func (s *Service) DepositsWithdrawals(start, end string) (*DepositsWithdrawalsResult, error) {
+	path := "/api2/1/private/depositsWithdrawals"
+	values := url.Values{}
+	values.Set("start", start)
+	values.Set("end", end)
+	res := new(DepositsWithdrawalsResult)
+	err := s.requestPrivate(path, values, res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
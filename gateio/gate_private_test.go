@@ -0,0 +1,30 @@
+package gateio
+
+import "testing"
+
+func TestCheckResult(t *testing.T) {
+	err := checkResult([]byte(`{"result":"false","code":21,"message":"Error: invalid key"}`))
+	e, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("expected *Error got %v", err)
+	}
+	if e.Code != 21 || e.Message != "Error: invalid key" {
+		t.Fatal(e)
+	}
+	if err := checkResult([]byte(`{"result":"true","message":"Success"}`)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckResultBool(t *testing.T) {
+	if err := checkResult([]byte(`{"result":false,"message":"Error: pair not found"}`)); err == nil {
+		t.Fatal("boolean false result should fail")
+	}
+	err := checkResult([]byte(`{"result":"false","code":"21","message":"Error: invalid key"}`))
+	if e, ok := err.(*Error); !ok || e.Message == "" {
+		t.Fatalf("string code should still fail with raw body, got %v", err)
+	}
+	if err := checkResult([]byte(`["eth_btc","ltc_btc"]`)); err != nil {
+		t.Fatal(err)
+	}
+}
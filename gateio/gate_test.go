@@ -77,4 +77,26 @@ func TestService_Balances(t *testing.T) {
 		t.Fatal(err)
 	}
 	t.Log(res)
-}
\ No newline at end of file
+}
+
+func TestService_OpenOrders(t *testing.T) {
+	s := NewService(testKey, testSecret)
+	res, err := s.OpenOrders()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range res {
+		t.Log(v)
+	}
+}
+
+func TestService_MyTradeHistory(t *testing.T) {
+	s := NewService(testKey, testSecret)
+	res, err := s.MyTradeHistory("gtc_usdt", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range res {
+		t.Log(v)
+	}
+}
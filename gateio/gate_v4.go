@@ -0,0 +1,342 @@
+package gateio
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-exchange/clock"
+)
+
+const (
+	defaultV4HOST = "api.gateio.ws"
+	v4Prefix      = "/api/v4"
+)
+
+// ServiceV4 gate.io APIv4 客户端
+type ServiceV4 struct {
+	apiKey string
+	secret string
+	clock  clock.Clock
+}
+
+// NewServiceV4 新建 APIv4 客户端 只调用公共接口时 apiKey secret 可以为空
+func NewServiceV4(apiKey, secret string) *ServiceV4 {
+	return &ServiceV4{
+		apiKey: apiKey,
+		secret: secret,
+	}
+}
+
+// SetClock 设置签名使用的时间来源 如 *clock.Sync 为 nil 时使用本机时间
+func (s *ServiceV4) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+func (s *ServiceV4) now() time.Time {
+	if s.clock != nil {
+		return s.clock.Now()
+	}
+	return time.Now()
+}
+
+// V4Error APIv4 返回非 2xx 时的错误信息
+type V4Error struct {
+	StatusCode int    `json:"-"`
+	Label      string `json:"label"`
+	Message    string `json:"message"`
+}
+
+func (e *V4Error) Error() string {
+	return fmt.Sprintf("gate v4 status %d label %s msg %s", e.StatusCode, e.Label, e.Message)
+}
+
+// signV4 APIv4 签名
+// method\npath\nquery\nhex(sha512(body))\ntimestamp 使用 secret 做 HMAC-SHA512
+func signV4(secret, method, path, query, body, ts string) string {
+	h := sha512.New()
+	h.Write([]byte(body))
+	hashed := hex.EncodeToString(h.Sum(nil))
+
+	src := strings.Join([]string{method, path, query, hashed, ts}, "\n")
+	mac := hmac.New(sha512.New, []byte(secret))
+	mac.Write([]byte(src))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// request 调用 APIv4 private 为 true 时签名
+func (s *ServiceV4) request(method, path string, query url.Values, body interface{}, private bool, target interface{}) error {
+	method = strings.ToUpper(method)
+	path = v4Prefix + path
+	rawQuery := query.Encode()
+
+	var payload []byte
+	if body != nil {
+		bs, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		payload = bs
+	}
+
+	u := url.URL{
+		Scheme:   defaultScheme,
+		Host:     defaultV4HOST,
+		Path:     path,
+		RawQuery: rawQuery,
+	}
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequest(method, u.String(), reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	if private {
+		ts := strconv.FormatInt(s.now().Unix(), 10)
+		req.Header.Set("KEY", s.apiKey)
+		req.Header.Set("Timestamp", ts)
+		req.Header.Set("SIGN", signV4(s.secret, method, path, rawQuery, string(payload), ts))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	bs, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		e := &V4Error{StatusCode: resp.StatusCode}
+		if err := json.Unmarshal(bs, e); err != nil || e.Label == "" {
+			e.Message = string(bs)
+		}
+		return e
+	}
+	if target == nil || len(bs) == 0 {
+		return nil
+	}
+	return json.Unmarshal(bs, target)
+}
+
+// ServerTime 查询服务器时间
+func (s *ServiceV4) ServerTime() (time.Time, error) {
+	res := new(struct {
+		ServerTime int64 `json:"server_time"`
+	})
+	err := s.request("GET", "/spot/time", nil, nil, false, res)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(res.ServerTime/1e3, res.ServerTime%1e3*1e6), nil
+}
+
+// V4CurrencyPair 交易对
+type V4CurrencyPair struct {
+	ID              string `json:"id"`
+	Base            string `json:"base"`
+	Quote           string `json:"quote"`
+	Fee             string `json:"fee"`
+	MinBaseAmount   string `json:"min_base_amount"`
+	MinQuoteAmount  string `json:"min_quote_amount"`
+	AmountPrecision int    `json:"amount_precision"`
+	Precision       int    `json:"precision"`
+	TradeStatus     string `json:"trade_status"`
+}
+
+// ListCurrencyPairs 查询所有交易对
+func (s *ServiceV4) ListCurrencyPairs() ([]V4CurrencyPair, error) {
+	res := make([]V4CurrencyPair, 0)
+	err := s.request("GET", "/spot/currency_pairs", nil, nil, false, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetCurrencyPair 查询单个交易对 eth_usdt
+func (s *ServiceV4) GetCurrencyPair(pair string) (*V4CurrencyPair, error) {
+	res := new(V4CurrencyPair)
+	err := s.request("GET", "/spot/currency_pairs/"+pair, nil, nil, false, res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// V4Ticker 行情
+type V4Ticker struct {
+	CurrencyPair     string `json:"currency_pair"`
+	Last             string `json:"last"`
+	LowestAsk        string `json:"lowest_ask"`
+	HighestBid       string `json:"highest_bid"`
+	ChangePercentage string `json:"change_percentage"`
+	BaseVolume       string `json:"base_volume"`
+	QuoteVolume      string `json:"quote_volume"`
+	High24h          string `json:"high_24h"`
+	Low24h           string `json:"low_24h"`
+}
+
+// ListTickers 查询行情 pair 为空时返回所有交易对
+func (s *ServiceV4) ListTickers(pair string) ([]V4Ticker, error) {
+	query := url.Values{}
+	if pair != "" {
+		query.Set("currency_pair", pair)
+	}
+	res := make([]V4Ticker, 0)
+	err := s.request("GET", "/spot/tickers", query, nil, false, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// V4OrderBook 市场深度 Asks 按价格从低到高 Bids 按价格从高到低
+type V4OrderBook struct {
+	ID      int64        `json:"id"`
+	Current int64        `json:"current"`
+	Update  int64        `json:"update"`
+	Asks    []PriceLevel `json:"asks"`
+	Bids    []PriceLevel `json:"bids"`
+}
+
+// ListOrderBook 查询市场深度 limit 为 0 时使用默认档数
+func (s *ServiceV4) ListOrderBook(pair string, limit int) (*V4OrderBook, error) {
+	query := url.Values{}
+	query.Set("currency_pair", pair)
+	query.Set("with_id", "true")
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	res := new(V4OrderBook)
+	err := s.request("GET", "/spot/order_book", query, nil, false, res)
+	if err != nil {
+		return nil, err
+	}
+	if err := sortLevels(res.Asks, res.Bids); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// V4Trade 成交记录 市场成交与我的成交共用
+type V4Trade struct {
+	ID           string `json:"id"`
+	CreateTime   string `json:"create_time"`
+	CreateTimeMs string `json:"create_time_ms"`
+	CurrencyPair string `json:"currency_pair"`
+	Side         string `json:"side"`
+	Role         string `json:"role"`
+	Amount       string `json:"amount"`
+	Price        string `json:"price"`
+	OrderID      string `json:"order_id"`
+	Fee          string `json:"fee"`
+	FeeCurrency  string `json:"fee_currency"`
+	PointFee     string `json:"point_fee"`
+	GtFee        string `json:"gt_fee"`
+}
+
+// ListTrades 查询市场成交记录 lastID 不为空时返回该 ID 之前的记录
+func (s *ServiceV4) ListTrades(pair string, limit int, lastID string) ([]V4Trade, error) {
+	query := url.Values{}
+	query.Set("currency_pair", pair)
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	if lastID != "" {
+		query.Set("last_id", lastID)
+	}
+	res := make([]V4Trade, 0)
+	err := s.request("GET", "/spot/trades", query, nil, false, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// V4Candlestick K 线
+type V4Candlestick struct {
+	Time        int64  // 开始时间 unix 秒
+	QuoteVolume string // 计价货币成交量
+	Close       string
+	High        string
+	Low         string
+	Open        string
+	BaseVolume  string // 基准货币成交量 部分接口不返回
+}
+
+// UnmarshalJSON K 线按位置返回
+// [时间, 计价货币成交量, 收盘价, 最高价, 最低价, 开盘价, 基准货币成交量]
+func (c *V4Candlestick) UnmarshalJSON(data []byte) error {
+	var fields []string
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	if len(fields) < 6 {
+		return fmt.Errorf("candlestick want at least 6 fields got %d", len(fields))
+	}
+	t, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("candlestick time %q: %v", fields[0], err)
+	}
+	c.Time = t
+	c.QuoteVolume = fields[1]
+	c.Close = fields[2]
+	c.High = fields[3]
+	c.Low = fields[4]
+	c.Open = fields[5]
+	if len(fields) > 6 {
+		c.BaseVolume = fields[6]
+	}
+	return nil
+}
+
+// CandlestickQuery K 线查询参数
+// Interval 取 10s 1m 5m 15m 30m 1h 4h 8h 1d 7d
+// From To 不为零时按时间范围查询 此时不能同时指定 Limit
+type CandlestickQuery struct {
+	CurrencyPair string
+	Interval     string
+	Limit        int
+	From         time.Time
+	To           time.Time
+}
+
+// ListCandlesticks 查询 K 线
+func (s *ServiceV4) ListCandlesticks(q *CandlestickQuery) ([]V4Candlestick, error) {
+	query := url.Values{}
+	query.Set("currency_pair", q.CurrencyPair)
+	if q.Interval != "" {
+		query.Set("interval", q.Interval)
+	}
+	if q.Limit > 0 {
+		query.Set("limit", strconv.Itoa(q.Limit))
+	}
+	if !q.From.IsZero() {
+		query.Set("from", strconv.FormatInt(q.From.Unix(), 10))
+	}
+	if !q.To.IsZero() {
+		query.Set("to", strconv.FormatInt(q.To.Unix(), 10))
+	}
+	res := make([]V4Candlestick, 0)
+	err := s.request("GET", "/spot/candlesticks", query, nil, false, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,56 @@
+package gateio
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSignV4(t *testing.T) {
+	sign := signV4("secret", "POST", "/api/v4/spot/orders", "", `{"currency_pair":"eth_usdt"}`, "1541993715")
+	want := "3839ebe96fa0f86cdc5cc1c088be16268f6e38f22055b8e6eaef7a6c8baa7ccf803b35d1551316dacd6809e64e6a8a3688d4833f2cbe1ffb4f8ca86e1d30e1db"
+	if sign != want {
+		t.Fatalf("sign %s want %s", sign, want)
+	}
+}
+
+func TestV4CandlestickUnmarshal(t *testing.T) {
+	var cs []V4Candlestick
+	data := `[["1539852480","971519.677","0.0021724","0.0021922","0.0021724","0.0021737","447.23"]]`
+	if err := json.Unmarshal([]byte(data), &cs); err != nil {
+		t.Fatal(err)
+	}
+	c := cs[0]
+	if c.Time != 1539852480 || c.Close != "0.0021724" || c.Open != "0.0021737" || c.BaseVolume != "447.23" {
+		t.Fatal(c)
+	}
+	if err := json.Unmarshal([]byte(`[["1539852480","1"]]`), &cs); err == nil {
+		t.Fatal("short candlestick should fail")
+	}
+}
+
+func TestServiceV4_ListTickers(t *testing.T) {
+	s := NewServiceV4("", "")
+	res, err := s.ListTickers("gt_usdt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(res)
+}
+
+func TestServiceV4_ListSpotAccounts(t *testing.T) {
+	s := NewServiceV4(testKey, testSecret)
+	res, err := s.ListSpotAccounts("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(res)
+}
+
+func TestServiceV4_ServerTime(t *testing.T) {
+	s := NewServiceV4("", "")
+	res, err := s.ServerTime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(res)
+}
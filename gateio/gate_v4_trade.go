@@ -0,0 +1,153 @@
+package gateio
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// V4Account 现货账户
+type V4Account struct {
+	Currency  string `json:"currency"`
+	Available string `json:"available"`
+	Locked    string `json:"locked"`
+}
+
+// ListSpotAccounts 查询现货账户 currency 为空时返回所有币种
+func (s *ServiceV4) ListSpotAccounts(currency string) ([]V4Account, error) {
+	query := url.Values{}
+	if currency != "" {
+		query.Set("currency", currency)
+	}
+	res := make([]V4Account, 0)
+	err := s.request("GET", "/spot/accounts", query, nil, true, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// V4Order 订单 下单时填写 Text CurrencyPair Type Account Side Amount Price TimeInForce
+type V4Order struct {
+	ID           string `json:"id,omitempty"`
+	Text         string `json:"text,omitempty"`
+	CreateTime   string `json:"create_time,omitempty"`
+	UpdateTime   string `json:"update_time,omitempty"`
+	Status       string `json:"status,omitempty"` // open closed cancelled
+	CurrencyPair string `json:"currency_pair"`
+	Type         string `json:"type,omitempty"`    // limit
+	Account      string `json:"account,omitempty"` // spot margin
+	Side         string `json:"side"`              // buy sell
+	Amount       string `json:"amount"`
+	Price        string `json:"price"`
+	TimeInForce  string `json:"time_in_force,omitempty"` // gtc ioc poc
+	Left         string `json:"left,omitempty"`
+	FilledTotal  string `json:"filled_total,omitempty"`
+	Fee          string `json:"fee,omitempty"`
+	FeeCurrency  string `json:"fee_currency,omitempty"`
+	PointFee     string `json:"point_fee,omitempty"`
+	GtFee        string `json:"gt_fee,omitempty"`
+
+	// 批量下单时每个订单的结果
+	Succeeded bool   `json:"succeeded,omitempty"`
+	Label     string `json:"label,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// CreateOrder 下单
+func (s *ServiceV4) CreateOrder(order *V4Order) (*V4Order, error) {
+	res := new(V4Order)
+	err := s.request("POST", "/spot/orders", nil, order, true, res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// CreateBatchOrders 批量下单 每个订单的结果看 Succeeded Label Message
+func (s *ServiceV4) CreateBatchOrders(orders []V4Order) ([]V4Order, error) {
+	res := make([]V4Order, 0)
+	err := s.request("POST", "/spot/batch_orders", nil, orders, true, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ListOrders 查询订单列表 status 取 open finished page 从 1 开始
+func (s *ServiceV4) ListOrders(pair, status string, page, limit int) ([]V4Order, error) {
+	query := url.Values{}
+	query.Set("currency_pair", pair)
+	query.Set("status", status)
+	if page > 0 {
+		query.Set("page", strconv.Itoa(page))
+	}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	res := make([]V4Order, 0)
+	err := s.request("GET", "/spot/orders", query, nil, true, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetOrder 查询单个订单
+func (s *ServiceV4) GetOrder(orderID, pair string) (*V4Order, error) {
+	query := url.Values{}
+	query.Set("currency_pair", pair)
+	res := new(V4Order)
+	err := s.request("GET", "/spot/orders/"+orderID, query, nil, true, res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// CancelOrder 撤销单个订单
+func (s *ServiceV4) CancelOrder(orderID, pair string) (*V4Order, error) {
+	query := url.Values{}
+	query.Set("currency_pair", pair)
+	res := new(V4Order)
+	err := s.request("DELETE", "/spot/orders/"+orderID, query, nil, true, res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// CancelOrders 撤销交易对下所有挂单 side 为空时撤销买卖两个方向
+func (s *ServiceV4) CancelOrders(pair, side string) ([]V4Order, error) {
+	query := url.Values{}
+	query.Set("currency_pair", pair)
+	if side != "" {
+		query.Set("side", side)
+	}
+	res := make([]V4Order, 0)
+	err := s.request("DELETE", "/spot/orders", query, nil, true, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ListMyTrades 查询我的成交 orderID 为空时返回交易对下全部成交
+func (s *ServiceV4) ListMyTrades(pair, orderID string, page, limit int) ([]V4Trade, error) {
+	query := url.Values{}
+	query.Set("currency_pair", pair)
+	if orderID != "" {
+		query.Set("order_id", orderID)
+	}
+	if page > 0 {
+		query.Set("page", strconv.Itoa(page))
+	}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	res := make([]V4Trade, 0)
+	err := s.request("GET", "/spot/my_trades", query, nil, true, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,44 @@
+package gateio
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Number 接口中的数值 可能是数字 数字字符串 或带千分位的字符串 空值按 0 处理
+type Number float64
+
+// UnmarshalJSON 解析数字或字符串
+func (n *Number) UnmarshalJSON(data []byte) error {
+	s := strings.TrimSpace(string(data))
+	if s == "null" || s == `""` {
+		*n = 0
+		return nil
+	}
+	f, err := parseNumber(json.RawMessage(strings.Replace(s, ",", "", -1)))
+	if err != nil {
+		return err
+	}
+	*n = Number(f)
+	return nil
+}
+
+// Float64 返回 float64
+func (n Number) Float64() float64 {
+	return float64(n)
+}
+
+// parseNumber 解析数字或数字字符串
+func parseNumber(raw json.RawMessage) (float64, error) {
+	var f float64
+	if err := json.Unmarshal(raw, &f); err == nil {
+		return f, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return 0, fmt.Errorf("%s is neither number nor string", string(raw))
+	}
+	return strconv.ParseFloat(s, 64)
+}
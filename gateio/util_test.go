@@ -0,0 +1,31 @@
+package gateio
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMarketListItemUnmarshal(t *testing.T) {
+	data := `{"no":1,"symbol":"LTC","pair":"ltc_usdt","rate":"418.88","vol_a":16833.478,"vol_b":"7,102,604","rate_percent":"-19.80","supply":25152514,"marketcap":"10,535,673,585"}`
+	item := new(MarketListItem)
+	if err := json.Unmarshal([]byte(data), item); err != nil {
+		t.Fatal(err)
+	}
+	if item.Rate != 418.88 || item.VolB != 7102604 || item.RatePercent != -19.8 || item.MarketCap != 10535673585 {
+		t.Fatal(item)
+	}
+}
+
+func TestTickerUnmarshal(t *testing.T) {
+	data := `{"result":"true","last":0.1,"lowestAsk":"0.11","highestBid":"","percentChange":null,"baseVolume":"12.5"}`
+	tk := new(Ticker)
+	if err := json.Unmarshal([]byte(data), tk); err != nil {
+		t.Fatal(err)
+	}
+	if tk.Last != 0.1 || tk.LowestAsk != 0.11 || tk.HighestBid != 0 || tk.BaseVolume.Float64() != 12.5 {
+		t.Fatal(tk)
+	}
+	if err := json.Unmarshal([]byte(`{"last":"x"}`), tk); err == nil {
+		t.Fatal("invalid number should fail")
+	}
+}
@@ -0,0 +1,142 @@
+package history
+
+import (
+	"path/filepath"
+	"strconv"
+
+	"go-exchange/batch"
+	"go-exchange/fcoin"
+)
+
+// Resolutions fcoin 支持的所有 K 线周期
+var Resolutions = []string{"M1", "M3", "M5", "M15", "M30", "H1", "H4", "H6", "D1", "W1", "MN"}
+
+// resolutionSeconds 每个周期的秒数 MN 按最长的 31 天算 只用于判断缺口
+var resolutionSeconds = map[string]int{
+	"M1":  60,
+	"M3":  3 * 60,
+	"M5":  5 * 60,
+	"M15": 15 * 60,
+	"M30": 30 * 60,
+	"H1":  3600,
+	"H4":  4 * 3600,
+	"H6":  6 * 3600,
+	"D1":  86400,
+	"W1":  7 * 86400,
+	"MN":  31 * 86400,
+}
+
+// CandleSource K 线来源 *fcoin.FcoinService 实现了该接口
+type CandleSource interface {
+	GetMarketCandle(resolution, symbol, before string, limit int) ([]fcoin.MarketCandle, error)
+}
+
+// Downloader 从最新往回下载 K 线到 Dir/symbol/resolution.csv
+// 每页写入一次 中断后重新运行会先补齐最新数据 再补中间缺口 最后继续往更早下载
+type Downloader struct {
+	batch.Pacer // 两次请求之间的最小间隔
+	Source      CandleSource
+	Dir         string
+	Limit       int // 每页数量
+}
+
+// NewDownloader 新建下载器
+func NewDownloader(source CandleSource, dir string) *Downloader {
+	return &Downloader{
+		Source: source,
+		Dir:    dir,
+		Limit:  100,
+		Pacer:  batch.Pacer{Interval: fcoin.DefaultPageInterval},
+	}
+}
+
+// Path K 线文件路径
+func (d *Downloader) Path(symbol, resolution string) string {
+	return filepath.Join(d.Dir, symbol, resolution+".csv")
+}
+
+// Download 下载交易对的指定周期 不指定时下载所有周期 返回每个周期新增的数量
+func (d *Downloader) Download(symbol string, resolutions ...string) (map[string]int, error) {
+	if len(resolutions) == 0 {
+		resolutions = Resolutions
+	}
+	added := make(map[string]int)
+	for _, r := range resolutions {
+		n, err := d.DownloadOne(symbol, r)
+		added[r] = n
+		if err != nil {
+			return added, err
+		}
+	}
+	return added, nil
+}
+
+// DownloadOne 下载单个周期 返回新增数量
+func (d *Downloader) DownloadOne(symbol, resolution string) (int, error) {
+	store, err := OpenStore(d.Path(symbol, resolution))
+	if err != nil {
+		return 0, err
+	}
+	ids := store.IDs()
+	total := 0
+
+	// 最新的数据 直到碰到已有的 K 线 上次保存的最新一根可能尚未收盘 这里会用完整数据覆盖
+	stop := -1
+	if len(ids) > 0 {
+		stop = ids[len(ids)-1]
+	}
+	n, err := d.walk(store, symbol, resolution, "", stop)
+	total += n
+	if err != nil || len(ids) == 0 {
+		return total, err
+	}
+
+	// 中间缺口
+	step := resolutionSeconds[resolution]
+	for i := 1; i < len(ids); i++ {
+		if step > 0 && ids[i]-ids[i-1] > step {
+			n, err := d.walk(store, symbol, resolution, strconv.Itoa(ids[i]), ids[i-1])
+			total += n
+			if err != nil {
+				return total, err
+			}
+		}
+	}
+
+	// 更早的数据
+	n, err = d.walk(store, symbol, resolution, strconv.Itoa(ids[0]), -1)
+	total += n
+	return total, err
+}
+
+// walk 从 before 往回翻页 碰到 ID 不大于 stop 的 K 线 短页或游标不再前进时停止
+func (d *Downloader) walk(store *Store, symbol, resolution, before string, stop int) (int, error) {
+	total := 0
+	for {
+		d.Wait()
+		page, err := d.Source.GetMarketCandle(resolution, symbol, before, d.Limit)
+		if err != nil {
+			return total, err
+		}
+		if len(page) == 0 {
+			return total, nil
+		}
+		n, err := store.Append(page)
+		total += n
+		if err != nil {
+			return total, err
+		}
+
+		oldest := page[0].ID
+		for _, c := range page {
+			if c.ID < oldest {
+				oldest = c.ID
+			}
+		}
+		next := strconv.Itoa(oldest)
+		if len(page) < d.Limit || next == before || (stop >= 0 && oldest <= stop) {
+			return total, nil
+		}
+		before = next
+	}
+}
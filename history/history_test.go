@@ -0,0 +1,162 @@
+package history
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"go-exchange/fcoin"
+)
+
+// fakeSource 返回 ID 小于 before 的 M1 K 线 available 中的 ID 才会返回
+type fakeSource struct {
+	available map[int]bool
+	newest    int
+	calls     int
+}
+
+func (f *fakeSource) GetMarketCandle(resolution, symbol, before string, limit int) ([]fcoin.MarketCandle, error) {
+	f.calls++
+	b, err := strconv.Atoi(before)
+	page := make([]fcoin.MarketCandle, 0)
+	for id := f.newest; id > 0 && len(page) < limit; id -= 60 {
+		if (err == nil && id >= b) || !f.available[id] {
+			continue
+		}
+		page = append(page, fcoin.MarketCandle{ID: id, Open: float64(id), Close: 1.5})
+	}
+	return page, nil
+}
+
+func newFakeSource(newest, oldest int) *fakeSource {
+	f := &fakeSource{available: make(map[int]bool), newest: newest}
+	for id := oldest; id <= newest; id += 60 {
+		f.available[id] = true
+	}
+	return f
+}
+
+func TestStoreRecoversTruncatedLine(t *testing.T) {
+	dir, err := ioutil.TempDir("", "history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "btcusdt", "M1.csv")
+
+	s, err := OpenStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Append([]fcoin.MarketCandle{{ID: 60, Open: 1.25}, {ID: 120, Open: 2}}); err != nil {
+		t.Fatal(err)
+	}
+	n, err := s.Append([]fcoin.MarketCandle{{ID: 120}, {ID: 180}})
+	if err != nil || n != 1 {
+		t.Fatalf("append %d %v", n, err)
+	}
+
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString("240,0,1")
+	f.Close()
+
+	s, err = OpenStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Len() != 3 || s.Candles()[0].Open != 1.25 {
+		t.Fatal(s.Candles())
+	}
+	if _, err := s.Append([]fcoin.MarketCandle{{ID: 240}}); err != nil {
+		t.Fatal(err)
+	}
+	s, err = OpenStore(path)
+	if err != nil || s.Len() != 4 {
+		t.Fatalf("reopen %v len %d", err, s.Len())
+	}
+}
+
+func TestStoreReplacesOpenBar(t *testing.T) {
+	dir, err := ioutil.TempDir("", "history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "btcusdt", "M1.csv")
+
+	s, err := OpenStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 下载时最新一根还没收盘 之后重新下载到完整的数据
+	if _, err := s.Append([]fcoin.MarketCandle{{ID: 60, Close: 1, Count: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	n, err := s.Append([]fcoin.MarketCandle{{ID: 60, Close: 2, Count: 5}, {ID: 120, Close: 3}})
+	if err != nil || n != 1 {
+		t.Fatalf("append %d %v", n, err)
+	}
+	if c := s.Candles()[0]; c.Close != 2 || c.Count != 5 {
+		t.Fatal("open bar should be replaced", c)
+	}
+	before, _ := ioutil.ReadFile(path)
+	if n, err := s.Append([]fcoin.MarketCandle{{ID: 60, Close: 2, Count: 5}}); err != nil || n != 0 {
+		t.Fatalf("append %d %v", n, err)
+	}
+	if after, _ := ioutil.ReadFile(path); len(after) != len(before) {
+		t.Fatal("unchanged bar should not be written again")
+	}
+
+	s, err = OpenStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := s.Candles()[0]; s.Len() != 2 || c.Close != 2 || c.Count != 5 {
+		t.Fatal("last line should win on load", s.Candles())
+	}
+}
+
+func TestDownloaderResumeAndFillGaps(t *testing.T) {
+	dir, err := ioutil.TempDir("", "history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// 第一次只有 [6000, 9000] 中间缺 [7200, 7800]
+	src := newFakeSource(9000, 6000)
+	for id := 7200; id <= 7800; id += 60 {
+		delete(src.available, id)
+	}
+	d := NewDownloader(src, dir)
+	d.Limit, d.Interval = 10, 0
+	if _, err := d.DownloadOne("btcusdt", "M1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 第二次 数据齐全 更早更新的数据也有了
+	src = newFakeSource(12000, 60)
+	d.Source = src
+	added, err := d.DownloadOne("btcusdt", "M1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := OpenStore(d.Path("btcusdt", "M1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.Len() != 200 {
+		t.Fatalf("have %d candles want 200", store.Len())
+	}
+	ids := store.IDs()
+	for i := 1; i < len(ids); i++ {
+		if ids[i]-ids[i-1] != 60 {
+			t.Fatalf("gap between %d and %d", ids[i-1], ids[i])
+		}
+	}
+	if added != 200-40 {
+		t.Fatalf("added %d", added)
+	}
+}
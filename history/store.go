@@ -0,0 +1,195 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go-exchange/fcoin"
+)
+
+// csvHeader 文件首行
+const csvHeader = "id,seq,open,close,high,low,count,base_vol,quote_vol"
+
+// Store 单个交易对单个周期的 K 线文件 CSV 格式 只追加不改写
+// 行的顺序不保证 读取时按 ID 去重 同一 ID 以最后一行为准 写到一半中断的最后一行在打开时丢弃
+type Store struct {
+	path string
+	bars map[int]fcoin.MarketCandle
+}
+
+// OpenStore 打开或新建 K 线文件
+func OpenStore(path string) (*Store, error) {
+	s := &Store{path: path, bars: make(map[int]fcoin.MarketCandle)}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, ioutil.WriteFile(path, []byte(csvHeader+"\n"), 0644)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 中断时最后一行可能不完整 截掉它 后续追加从完整行开始
+	if n := bytes.LastIndexByte(data, '\n'); n != len(data)-1 {
+		if n < 0 {
+			return s, ioutil.WriteFile(path, []byte(csvHeader+"\n"), 0644)
+		}
+		data = data[:n+1]
+		if err := os.Truncate(path, int64(n+1)); err != nil {
+			return nil, err
+		}
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	for i, line := range lines {
+		if line == "" || line == csvHeader {
+			continue
+		}
+		c, err := parseLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: %v", path, i+1, err)
+		}
+		s.bars[c.ID] = c
+	}
+	return s, nil
+}
+
+// Len 已保存的 K 线数量
+func (s *Store) Len() int {
+	return len(s.bars)
+}
+
+// Has 是否已有该 ID
+func (s *Store) Has(id int) bool {
+	_, ok := s.bars[id]
+	return ok
+}
+
+// IDs 所有 ID 从小到大
+func (s *Store) IDs() []int {
+	ids := make([]int, 0, len(s.bars))
+	for id := range s.bars {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// Candles 所有 K 线 按 ID 从小到大
+func (s *Store) Candles() []fcoin.MarketCandle {
+	res := make([]fcoin.MarketCandle, 0, len(s.bars))
+	for _, id := range s.IDs() {
+		res = append(res, s.bars[id])
+	}
+	return res
+}
+
+// Append 追加 K 线 返回新增数量
+// 已有但内容不同的 K 线 (如下载时尚未收盘的最新一根) 也追加一行 读取时后写的覆盖先写的
+func (s *Store) Append(cs []fcoin.MarketCandle) (int, error) {
+	var buf bytes.Buffer
+	prev := make(map[int]*fcoin.MarketCandle)
+	fresh := 0
+	for _, c := range cs {
+		line := formatLine(c)
+		old, ok := s.bars[c.ID]
+		if ok && formatLine(old) == line {
+			continue
+		}
+		if _, seen := prev[c.ID]; !seen {
+			if ok {
+				prev[c.ID] = &old
+			} else {
+				prev[c.ID] = nil
+				fresh++
+			}
+		}
+		s.bars[c.ID] = c
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+	if buf.Len() == 0 {
+		return 0, nil
+	}
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		s.restore(prev)
+		return 0, err
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		s.restore(prev)
+		return 0, err
+	}
+	if err := w.Flush(); err != nil {
+		s.restore(prev)
+		return 0, err
+	}
+	return fresh, f.Sync()
+}
+
+// restore 写入失败时恢复内存中的旧值 nil 表示原来没有
+func (s *Store) restore(prev map[int]*fcoin.MarketCandle) {
+	for id, c := range prev {
+		if c == nil {
+			delete(s.bars, id)
+		} else {
+			s.bars[id] = *c
+		}
+	}
+}
+
+func formatLine(c fcoin.MarketCandle) string {
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	return strings.Join([]string{
+		strconv.Itoa(c.ID), strconv.Itoa(c.Seq),
+		f(c.Open), f(c.Close), f(c.High), f(c.Low),
+		strconv.Itoa(c.Count), f(c.BaseVol), f(c.QuoteVol),
+	}, ",")
+}
+
+func parseLine(line string) (fcoin.MarketCandle, error) {
+	var c fcoin.MarketCandle
+	fields := strings.Split(line, ",")
+	if len(fields) != 9 {
+		return c, fmt.Errorf("want 9 fields got %d", len(fields))
+	}
+	ints := []*int{&c.ID, &c.Seq}
+	for i, p := range ints {
+		v, err := strconv.Atoi(fields[i])
+		if err != nil {
+			return c, err
+		}
+		*p = v
+	}
+	floats := []*float64{&c.Open, &c.Close, &c.High, &c.Low}
+	for i, p := range floats {
+		v, err := strconv.ParseFloat(fields[2+i], 64)
+		if err != nil {
+			return c, err
+		}
+		*p = v
+	}
+	count, err := strconv.Atoi(fields[6])
+	if err != nil {
+		return c, err
+	}
+	c.Count = count
+	if c.BaseVol, err = strconv.ParseFloat(fields[7], 64); err != nil {
+		return c, err
+	}
+	if c.QuoteVol, err = strconv.ParseFloat(fields[8], 64); err != nil {
+		return c, err
+	}
+	return c, nil
+}
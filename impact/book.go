@@ -0,0 +1,117 @@
+package impact
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"go-exchange/bibox"
+	"go-exchange/fcoin"
+	"go-exchange/gateio"
+)
+
+// Level 一档深度
+type Level struct {
+	Price  float64
+	Amount float64 // 基准货币数量
+}
+
+// Book 统一的深度 Bids 价格从高到低 Asks 价格从低到高
+type Book struct {
+	Bids []Level
+	Asks []Level
+}
+
+// Mid 中间价 任一边为空时返回 0
+func (b *Book) Mid() float64 {
+	if len(b.Bids) == 0 || len(b.Asks) == 0 {
+		return 0
+	}
+	return (b.Bids[0].Price + b.Asks[0].Price) / 2
+}
+
+// normalize 排序并检查价格和数量 以及买一小于卖一
+func (b *Book) normalize() error {
+	sort.SliceStable(b.Bids, func(i, j int) bool { return b.Bids[i].Price > b.Bids[j].Price })
+	sort.SliceStable(b.Asks, func(i, j int) bool { return b.Asks[i].Price < b.Asks[j].Price })
+	for _, side := range [][]Level{b.Bids, b.Asks} {
+		for _, l := range side {
+			if l.Price <= 0 || l.Amount < 0 {
+				return fmt.Errorf("invalid level price %v amount %v", l.Price, l.Amount)
+			}
+		}
+	}
+	if len(b.Bids) > 0 && len(b.Asks) > 0 && b.Bids[0].Price >= b.Asks[0].Price {
+		return fmt.Errorf("crossed book: bid %v >= ask %v", b.Bids[0].Price, b.Asks[0].Price)
+	}
+	return nil
+}
+
+// FromFcoin 由 fcoin 深度生成
+func FromFcoin(md *fcoin.MarketDepth) (*Book, error) {
+	fb, err := md.Book()
+	if err != nil {
+		return nil, err
+	}
+	b := &Book{Bids: make([]Level, len(fb.Bids)), Asks: make([]Level, len(fb.Asks))}
+	for i, l := range fb.Bids {
+		b.Bids[i] = Level{Price: l.Price, Amount: l.Amount}
+	}
+	for i, l := range fb.Asks {
+		b.Asks[i] = Level{Price: l.Price, Amount: l.Amount}
+	}
+	return b, nil
+}
+
+// FromGate 由 gate api2 深度生成
+func FromGate(ob *gateio.OrderBook) (*Book, error) {
+	b := &Book{Bids: make([]Level, len(ob.Bids)), Asks: make([]Level, len(ob.Asks))}
+	for i, l := range ob.Bids {
+		b.Bids[i] = Level{Price: l.Price, Amount: l.Amount}
+	}
+	for i, l := range ob.Asks {
+		b.Asks[i] = Level{Price: l.Price, Amount: l.Amount}
+	}
+	return b, b.normalize()
+}
+
+// FromGateV4 由 gate v4 深度生成
+func FromGateV4(ob *gateio.V4OrderBook) (*Book, error) {
+	b := &Book{Bids: make([]Level, len(ob.Bids)), Asks: make([]Level, len(ob.Asks))}
+	for i, l := range ob.Bids {
+		b.Bids[i] = Level{Price: l.Price, Amount: l.Amount}
+	}
+	for i, l := range ob.Asks {
+		b.Asks[i] = Level{Price: l.Price, Amount: l.Amount}
+	}
+	return b, b.normalize()
+}
+
+// FromBibox 由 bibox 深度生成 价格和数量为字符串
+func FromBibox(d *bibox.DepthResult) (*Book, error) {
+	parse := func(orders []bibox.Order) ([]Level, error) {
+		levels := make([]Level, len(orders))
+		for i, o := range orders {
+			price, err := strconv.ParseFloat(o.Price, 64)
+			if err != nil {
+				return nil, err
+			}
+			amount, err := strconv.ParseFloat(o.Volume, 64)
+			if err != nil {
+				return nil, err
+			}
+			levels[i] = Level{Price: price, Amount: amount}
+		}
+		return levels, nil
+	}
+	bids, err := parse(d.Result.Bids)
+	if err != nil {
+		return nil, err
+	}
+	asks, err := parse(d.Result.Asks)
+	if err != nil {
+		return nil, err
+	}
+	b := &Book{Bids: bids, Asks: asks}
+	return b, b.normalize()
+}
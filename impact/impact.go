@@ -0,0 +1,103 @@
+package impact
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrEmptyBook 要吃的一边没有挂单
+var ErrEmptyBook = errors.New("empty book side")
+
+// Unit 下单数量的单位
+type Unit int
+
+// 下单数量的单位
+const (
+	Base  Unit = iota // 基准货币数量
+	Quote             // 计价货币金额 如市价买入花费的 usdt
+)
+
+// Result 市价单的预估成交
+type Result struct {
+	Side        string
+	Filled      float64 // 可成交的基准货币数量
+	Cost        float64 // 可成交的计价货币金额
+	BestPrice   float64 // 买入为卖一 卖出为买一
+	AvgPrice    float64 // 成交均价
+	WorstPrice  float64 // 最后一档的价格
+	Mid         float64
+	SlippageBps float64 // 均价相对最优价的不利偏离 万分之一
+	ImpactBps   float64 // 均价相对中间价的不利偏离 一边为空时为 0
+	Levels      int     // 吃掉的档数 包括部分成交的最后一档
+	Complete    bool    // 可见深度足以成交全部数量
+	Remaining   float64 // 未能成交的数量 与 size 同单位
+}
+
+// Estimate 按可见深度预估市价单 buy 吃卖盘 sell 吃买盘 size 的单位由 unit 指定
+func Estimate(book *Book, side string, size float64, unit Unit) (Result, error) {
+	var levels []Level
+	switch side {
+	case "buy":
+		levels = book.Asks
+	case "sell":
+		levels = book.Bids
+	default:
+		return Result{}, fmt.Errorf("unknown side %q", side)
+	}
+	if size <= 0 {
+		return Result{}, fmt.Errorf("size must be positive, got %v", size)
+	}
+	if len(levels) == 0 {
+		return Result{}, ErrEmptyBook
+	}
+
+	r := Result{Side: side, BestPrice: levels[0].Price, Mid: book.Mid()}
+	left := size
+	for _, l := range levels {
+		if left <= 0 {
+			break
+		}
+		// 本档可成交的基准数量
+		take := l.Amount
+		if unit == Base && take > left {
+			take = left
+		}
+		if unit == Quote && take*l.Price > left {
+			take = left / l.Price
+		}
+		if take <= 0 {
+			continue
+		}
+		r.Filled += take
+		r.Cost += take * l.Price
+		r.WorstPrice = l.Price
+		r.Levels++
+		if unit == Base {
+			left -= take
+		} else {
+			left -= take * l.Price
+		}
+	}
+	// 浮点误差
+	if left <= size*1e-12 {
+		left = 0
+	}
+	r.Complete = left == 0
+	r.Remaining = left
+	if r.Filled > 0 {
+		r.AvgPrice = r.Cost / r.Filled
+		r.SlippageBps = adverseBps(side, r.AvgPrice, r.BestPrice)
+		if r.Mid > 0 {
+			r.ImpactBps = adverseBps(side, r.AvgPrice, r.Mid)
+		}
+	}
+	return r, nil
+}
+
+// adverseBps price 相对 ref 的不利偏离 买入价格越高越不利 卖出相反
+func adverseBps(side string, price, ref float64) float64 {
+	if side == "buy" {
+		return (price - ref) / ref * 1e4
+	}
+	return (ref - price) / ref * 1e4
+}
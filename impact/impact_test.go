@@ -0,0 +1,83 @@
+package impact
+
+import (
+	"math"
+	"testing"
+
+	"go-exchange/bibox"
+	"go-exchange/fcoin"
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func testBook(t *testing.T) *Book {
+	b, err := FromFcoin(&fcoin.MarketDepth{
+		Bids: []float64{99, 1, 98, 2, 97, 5},
+		Asks: []float64{101, 1, 102, 2, 104, 5},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestEstimateBase(t *testing.T) {
+	r, err := Estimate(testBook(t), "buy", 2, Base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Complete || r.Levels != 2 || !near(r.AvgPrice, 101.5) || r.WorstPrice != 102 {
+		t.Fatal(r)
+	}
+	if !near(r.SlippageBps, 0.5/101*1e4) || !near(r.ImpactBps, 1.5/100*1e4) {
+		t.Fatal(r.SlippageBps, r.ImpactBps)
+	}
+
+	r, err = Estimate(testBook(t), "sell", 10, Base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Complete || !near(r.Remaining, 2) || r.Levels != 3 || !near(r.Filled, 8) || r.WorstPrice != 97 {
+		t.Fatal(r)
+	}
+	if !near(r.SlippageBps, (99-r.AvgPrice)/99*1e4) || r.SlippageBps <= 0 {
+		t.Fatal(r.SlippageBps)
+	}
+}
+
+func TestEstimateQuote(t *testing.T) {
+	// 花 305 买入 101 + 204 正好两档
+	r, err := Estimate(testBook(t), "buy", 305, Quote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Complete || !near(r.Filled, 3) || !near(r.Cost, 305) || r.Levels != 2 {
+		t.Fatal(r)
+	}
+	r, err = Estimate(testBook(t), "buy", 50, Quote)
+	if err != nil || !near(r.Filled, 50.0/101) || r.SlippageBps != 0 || r.Levels != 1 {
+		t.Fatal(r, err)
+	}
+}
+
+func TestFromBibox(t *testing.T) {
+	d := new(bibox.DepthResult)
+	d.Result.Bids = []bibox.Order{{Price: "0.9", Volume: "10"}, {Price: "0.95", Volume: "1"}}
+	d.Result.Asks = []bibox.Order{{Price: "1.1", Volume: "3"}}
+	b, err := FromBibox(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Bids[0].Price != 0.95 || !near(b.Mid(), 1.025) {
+		t.Fatal(b)
+	}
+	d.Result.Asks[0].Price = "0.9"
+	if _, err := FromBibox(d); err == nil {
+		t.Fatal("crossed book")
+	}
+	if _, err := Estimate(&Book{}, "buy", 1, Base); err != ErrEmptyBook {
+		t.Fatal(err)
+	}
+}
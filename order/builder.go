@@ -0,0 +1,181 @@
+package order
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"go-exchange/bibox"
+	"go-exchange/gateio"
+)
+
+// Side 订单方向
+type Side string
+
+// 订单方向
+const (
+	Buy  Side = "buy"
+	Sell Side = "sell"
+)
+
+// ErrUnknownPair 没有该交易对的规则
+var ErrUnknownPair = errors.New("unknown pair")
+
+// TooSmallError 取整后价格 数量或金额低于最小值
+type TooSmallError struct {
+	Pair  string
+	Field string // price amount notional
+	Value float64
+	Min   float64
+}
+
+func (e *TooSmallError) Error() string {
+	return fmt.Sprintf("%s %s %v below minimum %v", e.Pair, e.Field, e.Value, e.Min)
+}
+
+// Builder 按交易对规则生成限价单
+// 买单价格向下取整 卖单价格向上取整 都不会比给定价格更差 数量一律向下取整 不会超出可用余额
+type Builder struct {
+	rules map[string]Rules
+}
+
+// NewBuilder 新建下单器 rules 为交易对到规则的映射 如 FcoinRules 的结果
+func NewBuilder(rules map[string]Rules) *Builder {
+	b := &Builder{rules: make(map[string]Rules, len(rules))}
+	for pair, r := range rules {
+		b.rules[pair] = r
+	}
+	return b
+}
+
+// Set 添加或替换单个交易对的规则 bibox 没有精度接口 需要手动设置
+func (b *Builder) Set(r Rules) {
+	b.rules[r.Pair] = r
+}
+
+// Rules 查询交易对的规则
+func (b *Builder) Rules(pair string) (Rules, bool) {
+	r, ok := b.rules[pair]
+	return r, ok
+}
+
+// Limit 生成限价单
+func (b *Builder) Limit(pair string, side Side, price, amount float64) (*Order, error) {
+	r, ok := b.rules[pair]
+	if !ok {
+		return nil, ErrUnknownPair
+	}
+	if side != Buy && side != Sell {
+		return nil, fmt.Errorf("unknown side %q", side)
+	}
+	if price <= 0 || amount <= 0 {
+		return nil, fmt.Errorf("price %v and amount %v must be positive", price, amount)
+	}
+
+	if side == Buy {
+		price = roundDown(price, r.PriceDecimal)
+	} else {
+		price = roundUp(price, r.PriceDecimal)
+	}
+	amount = roundDown(amount, r.AmountDecimal)
+	if price <= 0 {
+		return nil, &TooSmallError{Pair: pair, Field: "price", Value: price, Min: pow10(-r.PriceDecimal)}
+	}
+	if amount <= 0 || amount < r.MinAmount {
+		min := math.Max(r.MinAmount, pow10(-r.AmountDecimal))
+		return nil, &TooSmallError{Pair: pair, Field: "amount", Value: amount, Min: min}
+	}
+	if notional := price * amount; notional < r.MinNotional {
+		return nil, &TooSmallError{Pair: pair, Field: "notional", Value: notional, Min: r.MinNotional}
+	}
+
+	return &Order{
+		Pair:   pair,
+		Side:   side,
+		Price:  price,
+		Amount: amount,
+		rules:  r,
+	}, nil
+}
+
+// Amount 市价单数量 按精度向下取整并检查最小数量 返回取整后的数量及其文本
+func (b *Builder) Amount(pair string, amount float64) (float64, string, error) {
+	r, ok := b.rules[pair]
+	if !ok {
+		return 0, "", ErrUnknownPair
+	}
+	amount = roundDown(amount, r.AmountDecimal)
+	if amount <= 0 || amount < r.MinAmount {
+		min := math.Max(r.MinAmount, pow10(-r.AmountDecimal))
+		return 0, "", &TooSmallError{Pair: pair, Field: "amount", Value: amount, Min: min}
+	}
+	return amount, format(amount, r.AmountDecimal), nil
+}
+
+// Order 已按规则取整的限价单
+type Order struct {
+	Pair   string
+	Side   Side
+	Price  float64
+	Amount float64
+	rules  Rules
+}
+
+// PriceText 按精度格式化的价格
+func (o *Order) PriceText() string {
+	return format(o.Price, o.rules.PriceDecimal)
+}
+
+// AmountText 按精度格式化的数量
+func (o *Order) AmountText() string {
+	return format(o.Amount, o.rules.AmountDecimal)
+}
+
+// Notional 下单金额
+func (o *Order) Notional() float64 {
+	return o.Price * o.Amount
+}
+
+// Fcoin (*fcoin.FcoinService).CreateOrder 的参数
+func (o *Order) Fcoin() (symbol, side, orderType, price, amount string) {
+	return o.Pair, string(o.Side), "limit", o.PriceText(), o.AmountText()
+}
+
+// Gate api2 Buy 或 Sell 的参数 方向由调用哪个方法决定
+func (o *Order) Gate() *gateio.OrderRequest {
+	return &gateio.OrderRequest{
+		CurrencyPair: o.Pair,
+		Rate:         o.PriceText(),
+		Amount:       o.AmountText(),
+	}
+}
+
+// GateV4 v4 CreateOrder 的参数
+func (o *Order) GateV4() *gateio.V4Order {
+	return &gateio.V4Order{
+		CurrencyPair: o.Pair,
+		Type:         "limit",
+		Account:      "spot",
+		Side:         string(o.Side),
+		Amount:       o.AmountText(),
+		Price:        o.PriceText(),
+	}
+}
+
+// Bibox bibox Trade 的参数 金额同样按价格精度取整
+func (o *Order) Bibox(accountType bibox.AccountType) *bibox.TradeBody {
+	side := bibox.OrderSideBuy
+	if o.Side == Sell {
+		side = bibox.OrderSideSell
+	}
+	return &bibox.TradeBody{
+		Pair:        o.Pair,
+		AccountType: accountType,
+		OrderType:   bibox.OrderTypeLimit,
+		OrderSide:   side,
+		PayBix:      bibox.PayBixNo,
+		Price:       o.Price,
+		Amount:      o.Amount,
+		Money:       roundDown(o.Notional(), o.rules.PriceDecimal),
+	}
+}
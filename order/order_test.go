@@ -0,0 +1,109 @@
+package order
+
+import (
+	"testing"
+
+	"go-exchange/bibox"
+	"go-exchange/fcoin"
+	"go-exchange/gateio"
+)
+
+func TestBuilderRounding(t *testing.T) {
+	b := NewBuilder(FcoinRules([]fcoin.Symbol{
+		{Name: "btcusdt", PriceDecimal: 2, AmountDecimal: 4},
+	}))
+
+	buy, err := b.Limit("btcusdt", Buy, 7140.897, 0.30009)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buy.PriceText() != "7140.89" || buy.AmountText() != "0.3" {
+		t.Fatal(buy.PriceText(), buy.AmountText())
+	}
+	sell, err := b.Limit("btcusdt", Sell, 7140.891, 0.1*3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sell.PriceText() != "7140.9" || sell.AmountText() != "0.3" {
+		t.Fatal(sell.PriceText(), sell.AmountText())
+	}
+	symbol, side, typ, price, amount := sell.Fcoin()
+	if symbol != "btcusdt" || side != "sell" || typ != "limit" || price != "7140.9" || amount != "0.3" {
+		t.Fatal(symbol, side, typ, price, amount)
+	}
+
+	if _, err := b.Limit("ethusdt", Buy, 1, 1); err != ErrUnknownPair {
+		t.Fatal(err)
+	}
+	if _, err := b.Limit("btcusdt", Buy, 100, 0.00001); err == nil {
+		t.Fatal("amount rounds to zero")
+	}
+}
+
+func TestBuilderMinimums(t *testing.T) {
+	b := NewBuilder(GateV4Rules([]gateio.V4CurrencyPair{
+		{ID: "ETH_USDT", Precision: 2, AmountPrecision: 3, MinBaseAmount: "0.01", MinQuoteAmount: "1"},
+	}))
+
+	if _, err := b.Limit("ETH_USDT", Buy, 200, 0.009); err == nil {
+		t.Fatal("below min amount")
+	} else if e, ok := err.(*TooSmallError); !ok || e.Field != "amount" {
+		t.Fatal(err)
+	}
+	if _, err := b.Limit("ETH_USDT", Buy, 50, 0.019); err == nil {
+		t.Fatal("below min notional")
+	} else if e, ok := err.(*TooSmallError); !ok || e.Field != "notional" {
+		t.Fatal(err)
+	}
+
+	o, err := b.Limit("ETH_USDT", Sell, 200.001, 0.0129)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v4 := o.GateV4()
+	if v4.Price != "200.01" || v4.Amount != "0.012" || v4.Side != "sell" {
+		t.Fatal(v4)
+	}
+}
+
+func TestBuilderBibox(t *testing.T) {
+	b := NewBuilder(nil)
+	b.Set(Rules{Pair: "BIX_ETH", PriceDecimal: 6, AmountDecimal: 2})
+	o, err := b.Limit("BIX_ETH", Sell, 0.0012345678, 10.555)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tb := o.Bibox(bibox.AccountTypeCommon)
+	if tb.OrderSide != bibox.OrderSideSell || tb.Price != 0.001235 || tb.Amount != 10.55 {
+		t.Fatal(tb)
+	}
+	if err := tb.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuilderAmount(t *testing.T) {
+	b := NewBuilder(map[string]Rules{"ftusdt": {Pair: "ftusdt", AmountDecimal: 2, MinAmount: 1}})
+	if v, text, err := b.Amount("ftusdt", 12.349); err != nil || v != 12.34 || text != "12.34" {
+		t.Fatal(v, text, err)
+	}
+	if _, _, err := b.Amount("ftusdt", 0.999); err == nil {
+		t.Fatal("below min amount")
+	}
+}
+
+func TestRulesMarketable(t *testing.T) {
+	r := Rules{PriceDecimal: 2, AmountDecimal: 3}
+	if p := r.Marketable(Buy, 100.011); p != 100.02 {
+		t.Fatal(p)
+	}
+	if p := r.Marketable(Sell, 100.019); p != 100.01 {
+		t.Fatal(p)
+	}
+	if p := r.Marketable(Buy, 0.1*3); p != 0.3 {
+		t.Fatal(p)
+	}
+	if a := r.RoundAmount(1.23456); a != 1.234 {
+		t.Fatal(a)
+	}
+}
@@ -0,0 +1,119 @@
+package order
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"go-exchange/fcoin"
+	"go-exchange/gateio"
+)
+
+// Rules 交易对的下单规则
+type Rules struct {
+	Pair          string  // 交易所中的交易对名称
+	PriceDecimal  int     // 价格小数位数
+	AmountDecimal int     // 数量小数位数
+	MinAmount     float64 // 最小下单数量 0 表示不限制
+	MinNotional   float64 // 最小下单金额 价格 * 数量 0 表示不限制
+}
+
+// gateAmountDecimal api2 的 marketinfo 不返回数量精度 按 gate 的默认 8 位处理
+const gateAmountDecimal = 8
+
+// FcoinRules 由 GetSymbols 的结果生成规则 fcoin 不返回最小下单量
+func FcoinRules(symbols []fcoin.Symbol) map[string]Rules {
+	res := make(map[string]Rules, len(symbols))
+	for _, s := range symbols {
+		res[s.Name] = Rules{
+			Pair:          s.Name,
+			PriceDecimal:  s.PriceDecimal,
+			AmountDecimal: s.AmountDecimal,
+		}
+	}
+	return res
+}
+
+// GateRules 由 api2 MarketInfo 的结果生成规则
+// min_amount_a 为最小基准货币数量 min_amount 与 min_amount_b 取较大者为最小下单金额
+func GateRules(info *gateio.MarketInfoResult) map[string]Rules {
+	res := make(map[string]Rules)
+	for _, pairs := range info.Pairs {
+		for pair, p := range pairs {
+			res[pair] = Rules{
+				Pair:          pair,
+				PriceDecimal:  int(p.DecimalPlaces),
+				AmountDecimal: gateAmountDecimal,
+				MinAmount:     p.MinAmountA,
+				MinNotional:   math.Max(p.MinAmount, p.MinAmountB),
+			}
+		}
+	}
+	return res
+}
+
+// GateV4Rules 由 v4 ListCurrencyPairs 的结果生成规则
+func GateV4Rules(pairs []gateio.V4CurrencyPair) map[string]Rules {
+	res := make(map[string]Rules, len(pairs))
+	for _, p := range pairs {
+		minBase, _ := strconv.ParseFloat(p.MinBaseAmount, 64)
+		minQuote, _ := strconv.ParseFloat(p.MinQuoteAmount, 64)
+		res[p.ID] = Rules{
+			Pair:          p.ID,
+			PriceDecimal:  p.Precision,
+			AmountDecimal: p.AmountPrecision,
+			MinAmount:     minBase,
+			MinNotional:   minQuote,
+		}
+	}
+	return res
+}
+
+// pow10 10 的 n 次方
+func pow10(n int) float64 {
+	return math.Pow(10, float64(n))
+}
+
+// snap 浮点误差内已经是整数时取整 避免 0.1*3 之类的值被多舍一位
+func snap(v float64) float64 {
+	r := math.Round(v)
+	if math.Abs(v-r) <= 1e-9*math.Max(1, math.Abs(v)) {
+		return r
+	}
+	return v
+}
+
+// roundDown 按小数位数向下取整
+func roundDown(v float64, decimal int) float64 {
+	p := pow10(decimal)
+	return math.Floor(snap(v*p)) / p
+}
+
+// roundUp 按小数位数向上取整
+func roundUp(v float64, decimal int) float64 {
+	p := pow10(decimal)
+	return math.Ceil(snap(v*p)) / p
+}
+
+// format 按小数位数格式化 去掉末尾多余的 0
+func format(v float64, decimal int) string {
+	s := strconv.FormatFloat(v, 'f', decimal, 64)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
+
+// Marketable 主动成交的限价按精度取整 买单向上 卖单向下 不会比给定价格更难成交
+// 结果已在精度上 Builder.Limit 不会再改变它
+func (r Rules) Marketable(side Side, price float64) float64 {
+	if side == Buy {
+		return roundUp(price, r.PriceDecimal)
+	}
+	return roundDown(price, r.PriceDecimal)
+}
+
+// RoundAmount 数量按精度向下取整
+func (r Rules) RoundAmount(amount float64) float64 {
+	return roundDown(amount, r.AmountDecimal)
+}
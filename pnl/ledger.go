@@ -0,0 +1,332 @@
+package pnl
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"go-exchange/venue"
+)
+
+// Method 成本计算方法
+type Method int
+
+// 成本计算方法
+const (
+	FIFO        Method = iota // 先进先出
+	AverageCost               // 移动加权平均
+)
+
+func (m Method) String() string {
+	switch m {
+	case FIFO:
+		return "fifo"
+	case AverageCost:
+		return "average"
+	}
+	return fmt.Sprintf("Method(%d)", int(m))
+}
+
+// RateFunc 成交时 1 单位 currency 折合记账币种的价格
+// 成交的计价货币或手续费币种不是记账币种时调用
+type RateFunc func(currency string, at time.Time) (float64, error)
+
+// lot FIFO 的一批持仓
+type lot struct {
+	qty  float64
+	cost float64
+}
+
+// position 单个资产的持仓 AverageCost 只用 lots[0]
+type position struct {
+	lots      []lot
+	realized  float64
+	unmatched float64 // 卖出超过持有的数量 按零成本处理
+}
+
+func (p *position) quantity() float64 {
+	q := 0.0
+	for _, l := range p.lots {
+		q += l.qty
+	}
+	return q
+}
+
+func (p *position) cost() float64 {
+	c := 0.0
+	for _, l := range p.lots {
+		c += l.cost
+	}
+	return c
+}
+
+// Ledger 由成交计算各资产的持仓 成本和已实现盈亏 所有金额以 Currency 计
+// 币币交易同时视为卖出计价货币 手续费按成交时的价值计入成本或从收入中扣除
+type Ledger struct {
+	Method   Method
+	Currency string
+	Rate     RateFunc
+
+	positions map[string]*position
+	fees      float64
+	seen      map[string]bool
+}
+
+// NewLedger 新建账本 rate 可以为 nil 此时只能处理以 currency 计价且手续费为基准货币或 currency 的成交
+func NewLedger(method Method, currency string, rate RateFunc) *Ledger {
+	return &Ledger{
+		Method:    method,
+		Currency:  strings.ToLower(currency),
+		Rate:      rate,
+		positions: make(map[string]*position),
+		seen:      make(map[string]bool),
+	}
+}
+
+func (l *Ledger) rate(currency string, at time.Time) (float64, error) {
+	if currency == l.Currency {
+		return 1, nil
+	}
+	if l.Rate == nil {
+		return 0, fmt.Errorf("no rate for %s", currency)
+	}
+	return l.Rate(currency, at)
+}
+
+func (l *Ledger) position(asset string) *position {
+	p, ok := l.positions[asset]
+	if !ok {
+		p = &position{}
+		l.positions[asset] = p
+	}
+	return p
+}
+
+// acquire 买入 记账币种本身不记持仓
+func (l *Ledger) acquire(asset string, qty, cost float64) {
+	if asset == l.Currency || qty <= 0 {
+		return
+	}
+	p := l.position(asset)
+	if l.Method == AverageCost && len(p.lots) > 0 {
+		p.lots[0].qty += qty
+		p.lots[0].cost += cost
+		return
+	}
+	p.lots = append(p.lots, lot{qty: qty, cost: cost})
+}
+
+// dispose 卖出 proceeds 为所得 差额计入已实现盈亏
+func (l *Ledger) dispose(asset string, qty, proceeds float64) {
+	if asset == l.Currency || qty <= 0 {
+		return
+	}
+	p := l.position(asset)
+	left, basis := qty, 0.0
+	for len(p.lots) > 0 && left > 0 {
+		head := &p.lots[0]
+		if head.qty <= left+1e-12 {
+			basis += head.cost
+			left -= head.qty
+			p.lots = p.lots[1:]
+			continue
+		}
+		part := head.cost * left / head.qty
+		basis += part
+		head.cost -= part
+		head.qty -= left
+		left = 0
+	}
+	if left > 1e-12 {
+		p.unmatched += left
+	}
+	p.realized += proceeds - basis
+}
+
+// entry 已折算价值的成交 prepare 生成 apply 记账
+type entry struct {
+	fill                     venue.Fill
+	base, quote, feeCurrency string
+	gross                    float64 // 计价货币数量
+	value                    float64 // 成交价值
+	feeValue                 float64 // 手续费价值
+}
+
+// duplicate 同一交易所的成交 ID 已经记过
+func (l *Ledger) duplicate(f venue.Fill) bool {
+	return f.ID != "" && l.seen[f.Venue+"/"+f.ID]
+}
+
+// prepare 检查方向并查询汇率 不改变账本
+func (l *Ledger) prepare(f venue.Fill) (*entry, error) {
+	if f.Side != "buy" && f.Side != "sell" {
+		return nil, fmt.Errorf("unknown side %q", f.Side)
+	}
+	e := &entry{
+		fill:        f,
+		base:        strings.ToLower(f.Base),
+		quote:       strings.ToLower(f.Quote),
+		feeCurrency: strings.ToLower(f.FeeCurrency),
+	}
+	quoteRate, err := l.rate(e.quote, f.Time)
+	if err != nil {
+		return nil, err
+	}
+	e.gross = f.Price * f.Amount
+	e.value = e.gross * quoteRate
+
+	// 手续费的价值 基准和计价货币按成交价折算 其他币种查询价格
+	if f.Fee > 0 {
+		switch e.feeCurrency {
+		case e.base:
+			e.feeValue = f.Fee * f.Price * quoteRate
+		case e.quote:
+			e.feeValue = f.Fee * quoteRate
+		default:
+			r, err := l.rate(e.feeCurrency, f.Time)
+			if err != nil {
+				return nil, err
+			}
+			e.feeValue = f.Fee * r
+		}
+	}
+	return e, nil
+}
+
+// apply 记账 重复的成交 ID 忽略
+func (l *Ledger) apply(e *entry) {
+	f := e.fill
+	if l.duplicate(f) {
+		return
+	}
+	if f.ID != "" {
+		l.seen[f.Venue+"/"+f.ID] = true
+	}
+	l.fees += e.feeValue
+
+	base, quote, feeCurrency := e.base, e.quote, e.feeCurrency
+	gross, value, feeValue := e.gross, e.value, e.feeValue
+	if f.Side == "buy" {
+		switch feeCurrency {
+		case base:
+			l.dispose(quote, gross, value)
+			l.acquire(base, f.Amount-f.Fee, value)
+		case quote:
+			l.dispose(quote, gross+f.Fee, value+feeValue)
+			l.acquire(base, f.Amount, value+feeValue)
+		default:
+			l.dispose(feeCurrency, f.Fee, feeValue)
+			l.dispose(quote, gross, value)
+			l.acquire(base, f.Amount, value+feeValue)
+		}
+		return
+	}
+	switch feeCurrency {
+	case base:
+		l.dispose(base, f.Amount+f.Fee, value)
+		l.acquire(quote, gross, value)
+	case quote:
+		l.dispose(base, f.Amount, value-feeValue)
+		l.acquire(quote, gross-f.Fee, value-feeValue)
+	default:
+		l.dispose(feeCurrency, f.Fee, feeValue)
+		l.dispose(base, f.Amount, value-feeValue)
+		l.acquire(quote, gross, value)
+	}
+}
+
+// Add 记入一笔成交 同一交易所重复的成交 ID 忽略
+// 出错时账本不变 同一笔成交可以在补上汇率后重试
+func (l *Ledger) Add(f venue.Fill) error {
+	if l.duplicate(f) {
+		return nil
+	}
+	e, err := l.prepare(f)
+	if err != nil {
+		return err
+	}
+	l.apply(e)
+	return nil
+}
+
+// AddAll 按时间顺序记入多笔成交 任一笔出错时都不记入
+func (l *Ledger) AddAll(fills []venue.Fill) error {
+	sorted := append([]venue.Fill(nil), fills...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+	entries := make([]*entry, 0, len(sorted))
+	for _, f := range sorted {
+		if l.duplicate(f) {
+			continue
+		}
+		e, err := l.prepare(f)
+		if err != nil {
+			return fmt.Errorf("fill %s/%s: %v", f.Venue, f.ID, err)
+		}
+		entries = append(entries, e)
+	}
+	for _, e := range entries {
+		l.apply(e)
+	}
+	return nil
+}
+
+// Position 单个资产的持仓和盈亏 未实现盈亏需要当前价格 见 Report
+type Position struct {
+	Asset      string
+	Quantity   float64
+	Cost       float64
+	AvgCost    float64
+	Realized   float64
+	Unmatched  float64 // 卖出时没有对应买入的数量 成本按 0 计
+	Price      float64
+	Value      float64
+	Unrealized float64
+	Priced     bool
+}
+
+// Report 盈亏报告
+type Report struct {
+	Method     Method
+	Currency   string
+	Positions  []Position
+	Realized   float64
+	Unrealized float64
+	Fees       float64 // 已支付的手续费价值 已包含在盈亏中
+	Unpriced   []string
+}
+
+// Report 用 prices 中的当前价格计算未实现盈亏 prices 为 1 单位资产折合记账币种的价格
+func (l *Ledger) Report(prices map[string]float64) *Report {
+	r := &Report{Method: l.Method, Currency: l.Currency, Fees: l.fees}
+	assets := make([]string, 0, len(l.positions))
+	for a := range l.positions {
+		assets = append(assets, a)
+	}
+	sort.Strings(assets)
+	for _, a := range assets {
+		p := l.positions[a]
+		pos := Position{
+			Asset:     a,
+			Quantity:  p.quantity(),
+			Cost:      p.cost(),
+			Realized:  p.realized,
+			Unmatched: p.unmatched,
+		}
+		if pos.Quantity > 0 {
+			pos.AvgCost = pos.Cost / pos.Quantity
+		}
+		if price, ok := prices[a]; ok {
+			pos.Price, pos.Priced = price, true
+			pos.Value = price * pos.Quantity
+			pos.Unrealized = pos.Value - pos.Cost
+		} else if math.Abs(pos.Quantity) > 1e-12 {
+			r.Unpriced = append(r.Unpriced, a)
+		}
+		r.Realized += pos.Realized
+		r.Unrealized += pos.Unrealized
+		r.Positions = append(r.Positions, pos)
+	}
+	return r
+}
@@ -0,0 +1,177 @@
+package pnl
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"go-exchange/venue"
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func at(sec int64) time.Time {
+	return time.Unix(sec, 0)
+}
+
+func TestFIFOAndAverage(t *testing.T) {
+	fills := []venue.Fill{
+		{ID: "1", Base: "eth", Quote: "usdt", Side: "buy", Price: 100, Amount: 1, Time: at(1)},
+		{ID: "2", Base: "eth", Quote: "usdt", Side: "buy", Price: 200, Amount: 1, Time: at(2)},
+		{ID: "3", Base: "eth", Quote: "usdt", Side: "sell", Price: 250, Amount: 1, Time: at(3)},
+	}
+
+	fifo := NewLedger(FIFO, "usdt", nil)
+	if err := fifo.AddAll(fills); err != nil {
+		t.Fatal(err)
+	}
+	r := fifo.Report(map[string]float64{"eth": 300})
+	// 卖出最早的 100 成本 剩余成本 200
+	if !near(r.Realized, 150) || !near(r.Unrealized, 100) || !near(r.Positions[0].Cost, 200) {
+		t.Fatal(r)
+	}
+
+	avg := NewLedger(AverageCost, "usdt", nil)
+	if err := avg.AddAll(fills); err != nil {
+		t.Fatal(err)
+	}
+	r = avg.Report(map[string]float64{"eth": 300})
+	if !near(r.Realized, 100) || !near(r.Unrealized, 150) || !near(r.Positions[0].AvgCost, 150) {
+		t.Fatal(r)
+	}
+
+	// 重复的成交忽略
+	if err := avg.Add(fills[0]); err != nil || !near(avg.Report(nil).Positions[0].Quantity, 1) {
+		t.Fatal(err, avg.Report(nil).Positions)
+	}
+}
+
+func TestFees(t *testing.T) {
+	rate := func(currency string, _ time.Time) (float64, error) {
+		return map[string]float64{"bix": 0.5, "btc": 5000}[currency], nil
+	}
+	l := NewLedger(FIFO, "usdt", rate)
+	fills := []venue.Fill{
+		// 手续费为基准货币 实际得到 0.99 eth 成本 100
+		{ID: "1", Base: "eth", Quote: "usdt", Side: "buy", Price: 100, Amount: 1, Fee: 0.01, FeeCurrency: "eth", Time: at(1)},
+		// 手续费为 bix
+		{ID: "2", Base: "bix", Quote: "usdt", Side: "buy", Price: 0.4, Amount: 100, Time: at(2)},
+		{ID: "3", Base: "eth", Quote: "usdt", Side: "sell", Price: 120, Amount: 0.99, Fee: 10, FeeCurrency: "bix", Time: at(3)},
+	}
+	if err := l.AddAll(fills); err != nil {
+		t.Fatal(err)
+	}
+	r := l.Report(map[string]float64{"bix": 0.5})
+	var eth, bix Position
+	for _, p := range r.Positions {
+		switch p.Asset {
+		case "eth":
+			eth = p
+		case "bix":
+			bix = p
+		}
+	}
+	// eth: 收入 118.8 - 手续费 5 - 成本 100
+	if !near(eth.Realized, 13.8) || !near(eth.Quantity, 0) {
+		t.Fatal(eth)
+	}
+	// bix: 10 个按 0.5 用掉 成本 0.4
+	if !near(bix.Realized, 1) || !near(bix.Quantity, 90) || !near(bix.Unrealized, 9) {
+		t.Fatal(bix)
+	}
+	if !near(r.Fees, 6) {
+		t.Fatal(r.Fees)
+	}
+}
+
+func TestCryptoToCrypto(t *testing.T) {
+	rate := func(currency string, at time.Time) (float64, error) {
+		if at.Unix() < 2 {
+			return 5000, nil
+		}
+		return 6000, nil
+	}
+	l := NewLedger(FIFO, "usdt", rate)
+	fills := []venue.Fill{
+		{ID: "1", Base: "btc", Quote: "usdt", Side: "buy", Price: 5000, Amount: 1, Time: at(1)},
+		// 用 0.5 btc 买 10 eth btc 此时值 6000
+		{ID: "2", Base: "eth", Quote: "btc", Side: "buy", Price: 0.05, Amount: 10, Fee: 0.001, FeeCurrency: "btc", Time: at(2)},
+	}
+	if err := l.AddAll(fills); err != nil {
+		t.Fatal(err)
+	}
+	r := l.Report(map[string]float64{"btc": 6000, "eth": 300})
+	btc, eth := r.Positions[0], r.Positions[1]
+	// 卖出 0.501 btc 所得 3006 成本 2505
+	if !near(btc.Realized, 501) || !near(btc.Quantity, 0.499) {
+		t.Fatal(btc)
+	}
+	if !near(eth.Cost, 3006) || !near(eth.Unrealized, -6) {
+		t.Fatal(eth)
+	}
+}
+
+func TestNoRate(t *testing.T) {
+	l := NewLedger(FIFO, "usdt", nil)
+	err := l.Add(venue.Fill{Base: "eth", Quote: "btc", Side: "buy", Price: 0.05, Amount: 1})
+	if err == nil {
+		t.Fatal("btc quote needs a rate")
+	}
+}
+
+func TestRetryAfterError(t *testing.T) {
+	rates := map[string]float64{}
+	rate := func(currency string, at time.Time) (float64, error) {
+		if r, ok := rates[currency]; ok {
+			return r, nil
+		}
+		return 0, errors.New("no rate for " + currency)
+	}
+	l := NewLedger(FIFO, "usdt", rate)
+	fill := venue.Fill{Venue: "a", ID: "1", Base: "eth", Quote: "btc", Side: "buy", Price: 0.05, Amount: 1, Fee: 0.001, FeeCurrency: "bnb"}
+	if err := l.Add(fill); err == nil {
+		t.Fatal("btc quote needs a rate")
+	}
+	rates["btc"] = 6000
+	if err := l.Add(fill); err == nil {
+		t.Fatal("bnb fee needs a rate")
+	}
+	if r := l.Report(nil); r.Fees != 0 || len(r.Positions) != 0 {
+		t.Fatal("failed fill changed the ledger", r)
+	}
+
+	rates["bnb"] = 10
+	if err := l.Add(fill); err != nil {
+		t.Fatal(err)
+	}
+	r := l.Report(nil)
+	if !near(r.Fees, 0.01) || len(r.Positions) != 3 {
+		t.Fatal(r)
+	}
+}
+
+func TestUnknownSide(t *testing.T) {
+	l := NewLedger(FIFO, "usdt", nil)
+	fills := []venue.Fill{
+		{Venue: "a", ID: "1", Base: "eth", Quote: "usdt", Side: "buy", Price: 200, Amount: 1, Fee: 1, FeeCurrency: "usdt", Time: at(1)},
+		{Venue: "a", ID: "2", Base: "eth", Quote: "usdt", Side: "SELL", Price: 210, Amount: 1, Fee: 1, FeeCurrency: "usdt", Time: at(2)},
+	}
+	if err := l.AddAll(fills); err == nil {
+		t.Fatal("unknown side should fail")
+	}
+	if r := l.Report(nil); r.Fees != 0 || len(r.Positions) != 0 {
+		t.Fatal("AddAll applied part of the batch", r)
+	}
+
+	fills[1].Side = "sell"
+	if err := l.AddAll(fills); err != nil {
+		t.Fatal(err)
+	}
+	r := l.Report(nil)
+	if !near(r.Fees, 2) || !near(r.Realized, 8) {
+		t.Fatal(r)
+	}
+}
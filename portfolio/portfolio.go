@@ -0,0 +1,282 @@
+package portfolio
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"go-exchange/batch"
+	"go-exchange/venue"
+)
+
+// DefaultBridges 没有直接交易对时用于换算价格的中间币种
+var DefaultBridges = []string{"usdt", "btc", "eth"}
+
+// Holding 单个交易所单个币种的持仓 Priced 为 false 时无法估值 Value 为 0
+type Holding struct {
+	Venue     string
+	Currency  string
+	Available float64
+	Frozen    float64
+	Total     float64
+	Price     float64 // 以计价币种表示的单价
+	Value     float64
+	Priced    bool
+}
+
+// VenueSummary 单个交易所的持仓与估值
+type VenueSummary struct {
+	Venue    string
+	Err      error
+	Holdings []Holding
+	Value    float64
+}
+
+// Portfolio 所有交易所的持仓 Consolidated 按币种合并 Venue 字段为空
+type Portfolio struct {
+	Quote        string
+	Venues       []VenueSummary
+	Consolidated []Holding
+	Value        float64
+	Unpriced     []string // 无法估值的币种
+	PriceErrors  []error  // 行情查询失败 没有交易对的情况不算
+}
+
+// Service 并发查询所有交易所余额 并用任一交易所的行情估值
+type Service struct {
+	Venues  []venue.Venue
+	Quote   string            // 计价币种 如 usdt
+	Bridges []string          // 换算价格的中间币种 为空时使用 DefaultBridges
+	Aliases map[string]string // 币种别名 如 xbt: btc
+	Tickers []venue.Ticker    // 行情来源 为空时使用实现了 venue.Ticker 的 Venues
+}
+
+// New 新建组合
+func New(quote string, venues ...venue.Venue) *Service {
+	return &Service{Venues: venues, Quote: quote}
+}
+
+func (s *Service) canonical(currency string) string {
+	currency = strings.ToLower(currency)
+	if alias, ok := s.Aliases[currency]; ok {
+		return strings.ToLower(alias)
+	}
+	return currency
+}
+
+// Snapshot 查询并估值 单个交易所失败记录在对应的 VenueSummary.Err 中
+func (s *Service) Snapshot() *Portfolio {
+	quote := s.canonical(s.Quote)
+	p := &Portfolio{Quote: quote, Venues: make([]VenueSummary, len(s.Venues))}
+	batch.Run(len(s.Venues), batch.Options{Concurrency: len(s.Venues)}, func(i int) error {
+		v := s.Venues[i]
+		p.Venues[i].Venue = v.Name()
+		balances, err := v.Balances()
+		if err != nil {
+			p.Venues[i].Err = err
+			return err
+		}
+		p.Venues[i].Holdings = s.holdings(v.Name(), balances)
+		return nil
+	})
+
+	prices := newPricer(s.tickers(), quote, s.bridges())
+	merged := make(map[string]*Holding)
+	unpriced := make(map[string]bool)
+	for i := range p.Venues {
+		vs := &p.Venues[i]
+		for j := range vs.Holdings {
+			h := &vs.Holdings[j]
+			h.Price, h.Priced = prices.price(h.Currency)
+			h.Value = h.Price * h.Total
+			vs.Value += h.Value
+			if !h.Priced {
+				unpriced[h.Currency] = true
+			}
+
+			m, ok := merged[h.Currency]
+			if !ok {
+				m = &Holding{Currency: h.Currency, Price: h.Price, Priced: h.Priced}
+				merged[h.Currency] = m
+			}
+			m.Available += h.Available
+			m.Frozen += h.Frozen
+			m.Total += h.Total
+			m.Value += h.Value
+		}
+		sortHoldings(vs.Holdings)
+		p.Value += vs.Value
+	}
+
+	for _, m := range merged {
+		p.Consolidated = append(p.Consolidated, *m)
+	}
+	sortHoldings(p.Consolidated)
+	for c := range unpriced {
+		p.Unpriced = append(p.Unpriced, c)
+	}
+	sort.Strings(p.Unpriced)
+	p.PriceErrors = prices.errs
+	return p
+}
+
+// holdings 合并同一币种 去掉零余额 估值之后再按价值排序
+func (s *Service) holdings(name string, balances []venue.Balance) []Holding {
+	byCurrency := make(map[string]*Holding)
+	for _, b := range balances {
+		if b.Total() <= 0 {
+			continue
+		}
+		c := s.canonical(b.Currency)
+		h, ok := byCurrency[c]
+		if !ok {
+			h = &Holding{Venue: name, Currency: c}
+			byCurrency[c] = h
+		}
+		h.Available += b.Available
+		h.Frozen += b.Frozen
+		h.Total += b.Total()
+	}
+	res := make([]Holding, 0, len(byCurrency))
+	for _, h := range byCurrency {
+		res = append(res, *h)
+	}
+	return res
+}
+
+// sortHoldings 按价值从大到小 价值相同按币种
+func sortHoldings(hs []Holding) {
+	sort.Slice(hs, func(i, j int) bool {
+		if hs[i].Value != hs[j].Value {
+			return hs[i].Value > hs[j].Value
+		}
+		return hs[i].Currency < hs[j].Currency
+	})
+}
+
+func (s *Service) tickers() []venue.Ticker {
+	if len(s.Tickers) > 0 {
+		return s.Tickers
+	}
+	res := make([]venue.Ticker, 0, len(s.Venues))
+	for _, v := range s.Venues {
+		if t, ok := v.(venue.Ticker); ok {
+			res = append(res, t)
+		}
+	}
+	return res
+}
+
+func (s *Service) bridges() []string {
+	if len(s.Bridges) > 0 {
+		return s.Bridges
+	}
+	return DefaultBridges
+}
+
+// pricer 查询并缓存币种到计价币种的价格
+type pricer struct {
+	tickers []venue.Ticker
+	quote   string
+	bridges []string
+
+	mu    sync.Mutex
+	cache map[string]float64 // base/quote 直接价格 0 表示没有
+	errs  []error
+}
+
+func newPricer(tickers []venue.Ticker, quote string, bridges []string) *pricer {
+	return &pricer{tickers: tickers, quote: quote, bridges: bridges, cache: make(map[string]float64)}
+}
+
+// price currency 以 quote 表示的价格 先找直接或反向交易对 再经过中间币种换算
+func (p *pricer) price(currency string) (float64, bool) {
+	if currency == p.quote {
+		return 1, true
+	}
+	if v := p.pair(currency, p.quote); v > 0 {
+		return v, true
+	}
+	for _, b := range p.bridges {
+		if b == currency || b == p.quote {
+			continue
+		}
+		if toBridge := p.pair(currency, b); toBridge > 0 {
+			if toQuote := p.pair(b, p.quote); toQuote > 0 {
+				return toBridge * toQuote, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// pair base 以 quote 表示的价格 也尝试反向交易对 没有时返回 0
+func (p *pricer) pair(base, quote string) float64 {
+	key := base + "/" + quote
+	p.mu.Lock()
+	v, ok := p.cache[key]
+	p.mu.Unlock()
+	if ok {
+		return v
+	}
+
+	v = p.last(base, quote)
+	if v <= 0 {
+		if inv := p.last(quote, base); inv > 0 {
+			v = 1 / inv
+		}
+	}
+	p.mu.Lock()
+	p.cache[key] = v
+	p.mu.Unlock()
+	return v
+}
+
+// last 依次询问每个行情来源 返回第一个有效价格 ErrNoPair 之外的错误记录下来
+func (p *pricer) last(base, quote string) float64 {
+	for _, t := range p.tickers {
+		v, err := t.LastPrice(base, quote)
+		if err == nil && v > 0 {
+			return v
+		}
+		if err != nil && err != venue.ErrNoPair {
+			p.mu.Lock()
+			p.errs = append(p.errs, fmt.Errorf("%s/%s: %v", base, quote, err))
+			p.mu.Unlock()
+		}
+	}
+	return 0
+}
+
+// Write 输出每个交易所及合并后的持仓和估值
+func (p *Portfolio) Write(w io.Writer) {
+	for _, v := range p.Venues {
+		if v.Err != nil {
+			fmt.Fprintf(w, "%s: error: %v\n", v.Venue, v.Err)
+			continue
+		}
+		fmt.Fprintf(w, "%s: %v %s\n", v.Venue, v.Value, p.Quote)
+		writeHoldings(w, v.Holdings, p.Quote)
+	}
+	fmt.Fprintf(w, "consolidated:\n")
+	writeHoldings(w, p.Consolidated, p.Quote)
+	fmt.Fprintf(w, "total %v %s\n", p.Value, p.Quote)
+	if len(p.Unpriced) > 0 {
+		fmt.Fprintf(w, "unpriced: %s\n", strings.Join(p.Unpriced, ","))
+	}
+	for _, err := range p.PriceErrors {
+		fmt.Fprintf(w, "price error: %v\n", err)
+	}
+}
+
+func writeHoldings(w io.Writer, hs []Holding, quote string) {
+	for _, h := range hs {
+		value := "unpriced"
+		if h.Priced {
+			value = fmt.Sprintf("%v %s", h.Value, quote)
+		}
+		fmt.Fprintf(w, "  %-8s total %v available %v frozen %v value %s\n", h.Currency, h.Total, h.Available, h.Frozen, value)
+	}
+}
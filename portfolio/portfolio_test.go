@@ -0,0 +1,127 @@
+package portfolio
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"go-exchange/venue"
+)
+
+type fakeVenue struct {
+	name      string
+	balances  []venue.Balance
+	err       error
+	prices    map[string]float64 // base/quote
+	tickerErr error
+}
+
+func (f *fakeVenue) Name() string                                      { return f.name }
+func (f *fakeVenue) Balances() ([]venue.Balance, error)                { return f.balances, f.err }
+func (f *fakeVenue) OpenOrders() ([]venue.Order, error)                { return nil, nil }
+func (f *fakeVenue) CancelOrder(o venue.Order) error                   { return nil }
+func (f *fakeVenue) MarketSell(b, q string, a float64) (string, error) { return "", nil }
+
+func (f *fakeVenue) LastPrice(base, quote string) (float64, error) {
+	if p, ok := f.prices[base+"/"+quote]; ok {
+		return p, nil
+	}
+	if f.tickerErr != nil {
+		return 0, f.tickerErr
+	}
+	return 0, venue.ErrNoPair
+}
+
+func TestSnapshot(t *testing.T) {
+	a := &fakeVenue{
+		name: "a",
+		balances: []venue.Balance{
+			{Currency: "BTC", Available: 1, Frozen: 1},
+			{Currency: "usdt", Available: 500},
+			{Currency: "ft", Available: 1000},
+			{Currency: "dust", Available: 0},
+		},
+		prices: map[string]float64{"btc/usdt": 7000, "ft/eth": 0.0005},
+	}
+	b := &fakeVenue{
+		name: "b",
+		balances: []venue.Balance{
+			{Currency: "xbt", Available: 0.5},
+			{Currency: "zzz", Available: 3},
+		},
+		// 只有反向交易对
+		prices: map[string]float64{"usdt/eth": 0.005},
+	}
+	c := &fakeVenue{name: "c", err: errors.New("down")}
+
+	s := New("USDT", a, b, c)
+	s.Aliases = map[string]string{"xbt": "btc"}
+	p := s.Snapshot()
+
+	if p.Venues[2].Err == nil || p.Venues[2].Venue != "c" {
+		t.Fatal(p.Venues[2])
+	}
+	va := p.Venues[0]
+	if len(va.Holdings) != 3 || va.Holdings[0].Currency != "btc" || va.Holdings[0].Value != 14000 {
+		t.Fatal(va.Holdings)
+	}
+	// 按价值排序 不是按币种
+	if va.Holdings[1].Currency != "usdt" || va.Holdings[2].Currency != "ft" {
+		t.Fatal(va.Holdings)
+	}
+	// ft 经 eth 换算 eth = 1 / 0.005 = 200 usdt
+	ftValue := 0.0
+	for _, h := range va.Holdings {
+		if h.Currency == "ft" {
+			ftValue = h.Value
+		}
+	}
+	if math.Abs(ftValue-100) > 1e-9 {
+		t.Fatal(ftValue)
+	}
+	if math.Abs(va.Value-14600) > 1e-9 {
+		t.Fatal(va.Value)
+	}
+
+	if p.Venues[1].Holdings[0].Currency != "btc" || p.Venues[1].Value != 3500 {
+		t.Fatal(p.Venues[1])
+	}
+	if len(p.Unpriced) != 1 || p.Unpriced[0] != "zzz" {
+		t.Fatal(p.Unpriced)
+	}
+	btc := p.Consolidated[0]
+	if btc.Currency != "btc" || btc.Total != 2.5 || btc.Value != 17500 || btc.Venue != "" {
+		t.Fatal(btc)
+	}
+	if math.Abs(p.Value-18100) > 1e-9 {
+		t.Fatal(p.Value)
+	}
+
+	var buf bytes.Buffer
+	p.Write(&buf)
+	if !strings.Contains(buf.String(), "c: error: down") || !strings.Contains(buf.String(), "total 18100 usdt") {
+		t.Fatal(buf.String())
+	}
+}
+
+func TestSnapshotPriceErrors(t *testing.T) {
+	a := &fakeVenue{
+		name:      "a",
+		balances:  []venue.Balance{{Currency: "ft", Available: 10}, {Currency: "usdt", Available: 1}},
+		tickerErr: errors.New("timeout"),
+	}
+	p := New("usdt", a).Snapshot()
+	if len(p.Unpriced) != 1 || p.Unpriced[0] != "ft" || len(p.PriceErrors) == 0 {
+		t.Fatal(p.Unpriced, p.PriceErrors)
+	}
+	if !strings.Contains(p.PriceErrors[0].Error(), "ft/usdt: timeout") {
+		t.Fatal(p.PriceErrors)
+	}
+	var buf bytes.Buffer
+	p.Write(&buf)
+	if !strings.Contains(buf.String(), "price error: ft/usdt: timeout") {
+		t.Fatal(buf.String())
+	}
+}
@@ -0,0 +1,477 @@
+package router
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"go-exchange/batch"
+	"go-exchange/fee"
+	"go-exchange/impact"
+	"go-exchange/order"
+	"go-exchange/venue"
+)
+
+// ErrNoVenue 没有可用的交易所 需要同时实现 venue.Depth 和 venue.Trader
+var ErrNoVenue = errors.New("no venue with depth and trading")
+
+// Router 把一笔大单按各交易所的深度 手续费和余额拆分到多个交易所
+// 所有交易所的档位按含手续费的价格排序 从最优开始分配 直到数量满足或深度和余额用完
+type Router struct {
+	Venues      []venue.Venue
+	Fees        *fee.Model    // 可以为 nil 此时不计手续费 设置时没有费率的交易所不参与 记入 Plan.Skipped
+	MinChild    float64       // 子订单的最小金额 计价货币 低于该值的交易所不参与分配
+	PriceBuffer float64       // 子订单限价在最差档价格上再让出的比例 提高成交概率
+	Wait        time.Duration // Execute 等待子订单成交的最长时间 0 表示不等待 超时未完成的子订单会被撤销
+	Poll        time.Duration // 查询子订单状态的间隔
+}
+
+// New 新建路由
+func New(fees *fee.Model, venues ...venue.Venue) *Router {
+	return &Router{Venues: venues, Fees: fees, Poll: time.Second}
+}
+
+// Child 分配到单个交易所的子订单
+type Child struct {
+	Venue       venue.Venue
+	Amount      float64 // 基准货币数量
+	LimitPrice  float64 // 限价 最差档价格加上 PriceBuffer 按交易所精度取整
+	ExpectedAvg float64 // 预计成交均价 不含手续费
+	Fee         float64 // 预计手续费 计价货币
+	Levels      int
+}
+
+// Cost 预计金额 买入为花费 卖出为所得 都已计入手续费
+func (c *Child) Cost(side string) float64 {
+	v := c.ExpectedAvg * c.Amount
+	if side == "buy" {
+		return v + c.Fee
+	}
+	return v - c.Fee
+}
+
+// Plan 拆单计划
+type Plan struct {
+	Base        string
+	Quote       string
+	Side        string
+	Amount      float64
+	Children    []Child
+	Unallocated float64            // 深度或余额不足未能分配的数量
+	Skipped     map[string]error   // 无法参与的交易所及原因
+	Dropped     map[string]float64 // 子订单过小而被排除的交易所及当时分到的数量
+}
+
+// ExpectedAvg 所有子订单的预计均价 含手续费
+func (p *Plan) ExpectedAvg() float64 {
+	amount, cost := 0.0, 0.0
+	for i := range p.Children {
+		amount += p.Children[i].Amount
+		cost += p.Children[i].Cost(p.Side)
+	}
+	if amount == 0 {
+		return 0
+	}
+	return cost / amount
+}
+
+// candidate 一个交易所的一档
+type candidate struct {
+	venue     int
+	price     float64
+	effective float64 // 含手续费的价格
+	amount    float64
+}
+
+// venueState 计划时每个交易所的数据
+type venueState struct {
+	v       venue.Venue
+	feeRate float64
+	balance float64      // 买入为可用计价货币 卖出为可用基准货币
+	rules   *order.Rules // 交易所的下单规则 nil 时不取整
+	levels  []candidate
+	err     error
+}
+
+// Plan 按当前深度 手续费和余额计算拆单计划 amount 为基准货币数量
+// 子订单的数量和限价按交易所规则取整 取整后低于 MinChild 或交易所最小下单量的交易所
+// 被排除 其数量重新分配给其他交易所 取整或余额限制少下的数量也重新分配给其他交易所
+func (r *Router) Plan(base, quote, side string, amount float64) (*Plan, error) {
+	base, quote = strings.ToLower(base), strings.ToLower(quote)
+	if side != "buy" && side != "sell" {
+		return nil, fmt.Errorf("unknown side %q", side)
+	}
+	if amount <= 0 {
+		return nil, fmt.Errorf("amount must be positive, got %v", amount)
+	}
+
+	states := make([]venueState, 0, len(r.Venues))
+	for _, v := range r.Venues {
+		_, okDepth := v.(venue.Depth)
+		_, okTrader := v.(venue.Trader)
+		if okDepth && okTrader {
+			states = append(states, venueState{v: v})
+		}
+	}
+	if len(states) == 0 {
+		return nil, ErrNoVenue
+	}
+	batch.Run(len(states), batch.Options{Concurrency: len(states)}, func(i int) error {
+		states[i].err = r.load(&states[i], i, base, quote, side)
+		return states[i].err
+	})
+
+	plan := &Plan{Base: base, Quote: quote, Side: side, Amount: amount,
+		Skipped: make(map[string]error), Dropped: make(map[string]float64)}
+	excluded := make([]bool, len(states))
+	caps := make([]float64, len(states))
+	for i, s := range states {
+		caps[i] = math.Inf(1)
+		if s.err != nil {
+			plan.Skipped[s.v.Name()] = s.err
+			excluded[i] = true
+		}
+	}
+	for {
+		children, allocated := r.allocate(states, excluded, caps, side, amount)
+		small := -1
+		for i, c := range children {
+			if c != nil && r.tooSmall(&states[i], c) && (small < 0 || c.Amount*c.ExpectedAvg < children[small].Amount*children[small].ExpectedAvg) {
+				small = i
+			}
+		}
+		if small >= 0 {
+			plan.Dropped[states[small].v.Name()] = children[small].Amount
+			excluded[small] = true
+			continue
+		}
+		// 取整后少于分到的数量 该交易所以取整后的数量为上限 差额交给其他交易所
+		reduced := false
+		for i, c := range children {
+			if c != nil && c.Amount < allocated[i]-amount*1e-12 {
+				caps[i] = c.Amount
+				reduced = true
+			}
+		}
+		if reduced {
+			continue
+		}
+		left := amount
+		for _, c := range children {
+			if c != nil {
+				plan.Children = append(plan.Children, *c)
+				left -= c.Amount
+			}
+		}
+		if left < amount*1e-12 {
+			left = 0
+		}
+		plan.Unallocated = left
+		return plan, nil
+	}
+}
+
+// allocate 把所有未排除交易所的档位按含手续费的价格排序 从最优开始分配 每个交易所最多分到 caps
+// 返回的子订单和取整前分到的数量都与 states 下标对应 没有分到数量的子订单为 nil
+func (r *Router) allocate(states []venueState, excluded []bool, caps []float64, side string, amount float64) ([]*Child, []float64) {
+	all := make([]candidate, 0)
+	budget := make([]float64, len(states))
+	for i, s := range states {
+		if !excluded[i] {
+			all = append(all, s.levels...)
+			budget[i] = s.balance
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		if side == "buy" {
+			return all[i].effective < all[j].effective
+		}
+		return all[i].effective > all[j].effective
+	})
+
+	taken := make([][]impact.Level, len(states))
+	allocated := make([]float64, len(states))
+	left := amount
+	for _, c := range all {
+		if left <= 0 {
+			break
+		}
+		take := math.Min(c.amount, left)
+		take = math.Min(take, caps[c.venue]-allocated[c.venue])
+		// 余额限制 买入按含手续费的金额
+		if side == "buy" {
+			take = math.Min(take, budget[c.venue]/c.effective)
+		} else {
+			take = math.Min(take, budget[c.venue])
+		}
+		if take <= 0 {
+			continue
+		}
+		if side == "buy" {
+			budget[c.venue] -= take * c.effective
+		} else {
+			budget[c.venue] -= take
+		}
+		left -= take
+		allocated[c.venue] += take
+		taken[c.venue] = append(taken[c.venue], impact.Level{Price: c.price, Amount: take})
+	}
+
+	children := make([]*Child, len(states))
+	for i, levels := range taken {
+		if len(levels) > 0 {
+			children[i] = r.child(&states[i], side, levels)
+		}
+	}
+	return children, allocated
+}
+
+// child 由分到的档位生成子订单 限价和数量按交易所规则取整
+// 买单的限价乘数量不超过余额 数量取整后从最差的档位扣减
+func (r *Router) child(s *venueState, side string, levels []impact.Level) *Child {
+	worst := levels[len(levels)-1].Price
+	amount := 0.0
+	for _, l := range levels {
+		amount += l.Amount
+	}
+	limit := worst * (1 + r.PriceBuffer)
+	if side == "sell" {
+		limit = worst * (1 - r.PriceBuffer)
+	}
+	if s.rules != nil {
+		limit = s.rules.Marketable(order.Side(side), limit)
+	}
+	if side == "buy" && limit*amount > s.balance {
+		amount = s.balance / limit
+	}
+	if s.rules != nil {
+		amount = s.rules.RoundAmount(amount)
+	}
+
+	c := &Child{Venue: s.v, Amount: amount, LimitPrice: limit}
+	left, value := amount, 0.0
+	for _, l := range levels {
+		if left <= 0 {
+			break
+		}
+		take := math.Min(l.Amount, left)
+		value += take * l.Price
+		left -= take
+		c.Levels++
+	}
+	if amount > 0 {
+		c.ExpectedAvg = value / amount
+	}
+	c.Fee = value * s.feeRate
+	return c
+}
+
+// tooSmall 子订单低于 MinChild 或交易所的最小数量和金额
+func (r *Router) tooSmall(s *venueState, c *Child) bool {
+	if c.Amount <= 0 || c.Amount*c.ExpectedAvg < r.MinChild {
+		return true
+	}
+	return s.rules != nil && (c.Amount < s.rules.MinAmount || c.Amount*c.LimitPrice < s.rules.MinNotional)
+}
+
+// load 查询费率 余额 下单规则和深度 没有费率时返回 fee.ErrNoRate
+func (r *Router) load(s *venueState, index int, base, quote, side string) error {
+	if r.Fees != nil {
+		rate, err := r.Fees.Rate(s.v.Name(), base, quote)
+		if err != nil {
+			return err
+		}
+		s.feeRate = rate.Effective(false)
+	}
+	balances, err := s.v.Balances()
+	if err != nil {
+		return err
+	}
+	want := quote
+	if side == "sell" {
+		want = base
+	}
+	for _, b := range balances {
+		if b.Currency == want {
+			s.balance += b.Available
+		}
+	}
+	if p, ok := s.v.(venue.Precision); ok {
+		rules, err := p.PairRules(base, quote)
+		if err != nil {
+			return err
+		}
+		s.rules = &rules
+	}
+
+	book, err := s.v.(venue.Depth).OrderBook(base, quote)
+	if err != nil {
+		return err
+	}
+	levels := book.Asks
+	if side == "sell" {
+		levels = book.Bids
+	}
+	for _, l := range levels {
+		c := candidate{venue: index, price: l.Price, amount: l.Amount}
+		if side == "buy" {
+			c.effective = l.Price * (1 + s.feeRate)
+		} else {
+			c.effective = l.Price * (1 - s.feeRate)
+		}
+		s.levels = append(s.levels, c)
+	}
+	return nil
+}
+
+// ChildReport 子订单的执行结果
+type ChildReport struct {
+	Child
+	OrderID   string
+	Err       error
+	Filled    float64
+	AvgPrice  float64
+	Done      bool
+	Canceled  bool  // 等待超时后已撤单
+	CancelErr error // 超时撤单失败的原因
+}
+
+// Open 子订单已下单且仍可能在挂单中
+func (c *ChildReport) Open() bool {
+	return c.OrderID != "" && c.Err == nil && !c.Done && !c.Canceled
+}
+
+// Report 母订单的执行结果
+type Report struct {
+	Plan     *Plan
+	Children []ChildReport
+	Filled   float64 // 所有子订单已成交的基准货币数量
+	Value    float64 // 已成交金额 不含手续费
+}
+
+// AvgPrice 所有子订单的成交均价
+func (r *Report) AvgPrice() float64 {
+	if r.Filled == 0 {
+		return 0
+	}
+	return r.Value / r.Filled
+}
+
+// Open 仍可能在挂单中的子订单 Wait 为 0 或交易所不支持撤单或撤单失败时由调用方处理
+func (r *Report) Open() []ChildReport {
+	res := make([]ChildReport, 0)
+	for _, c := range r.Children {
+		if c.Open() {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// OK 所有子订单都已下单 不表示已成交 挂单中的子订单见 Open
+func (r *Report) OK() bool {
+	for _, c := range r.Children {
+		if c.Err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Route 计算计划并执行
+func (r *Router) Route(base, quote, side string, amount float64) (*Report, error) {
+	plan, err := r.Plan(base, quote, side, amount)
+	if err != nil {
+		return nil, err
+	}
+	return r.Execute(plan), nil
+}
+
+// Execute 并发下所有子订单 Wait 大于 0 时等待成交并汇总 超时未完成的子订单撤单
+// 撤单后仍按最后查询到的成交汇总
+func (r *Router) Execute(plan *Plan) *Report {
+	report := &Report{Plan: plan, Children: make([]ChildReport, len(plan.Children))}
+	batch.Run(len(plan.Children), batch.Options{Concurrency: len(plan.Children)}, func(i int) error {
+		c := plan.Children[i]
+		cr := &report.Children[i]
+		cr.Child = c
+		id, err := c.Venue.(venue.Trader).PlaceLimit(plan.Base, plan.Quote, plan.Side, c.LimitPrice, c.Amount)
+		cr.OrderID, cr.Err = id, err
+		if err == nil && r.Wait > 0 {
+			r.wait(plan, cr)
+		}
+		return err
+	})
+	for _, c := range report.Children {
+		report.Filled += c.Filled
+		report.Value += c.Filled * c.AvgPrice
+	}
+	return report
+}
+
+// wait 轮询子订单直到完成或超时 交易所不支持查询时直接返回
+// 超时后交易所支持撤单时撤单 并再查询一次成交
+func (r *Router) wait(plan *Plan, cr *ChildReport) {
+	status, ok := cr.Venue.(venue.OrderStatus)
+	if !ok {
+		return
+	}
+	poll := r.Poll
+	if poll <= 0 {
+		poll = time.Second
+	}
+	update := func() {
+		if e, err := status.OrderStatus(plan.Base, plan.Quote, cr.OrderID); err == nil {
+			cr.Filled, cr.AvgPrice, cr.Done = e.Filled, e.AvgPrice, e.Done
+		}
+	}
+	deadline := time.Now().Add(r.Wait)
+	for {
+		update()
+		if cr.Done {
+			return
+		}
+		if time.Now().Add(poll).After(deadline) {
+			break
+		}
+		time.Sleep(poll)
+	}
+	canceler, ok := cr.Venue.(venue.Canceler)
+	if !ok {
+		return
+	}
+	if cr.CancelErr = canceler.CancelLimit(plan.Base, plan.Quote, cr.OrderID); cr.CancelErr == nil {
+		cr.Canceled = true
+		update()
+	}
+}
+
+// Write 输出可读的报告
+func (r *Report) Write(w io.Writer) {
+	p := r.Plan
+	fmt.Fprintf(w, "%s %v %s/%s expected avg %v unallocated %v\n", p.Side, p.Amount, p.Base, p.Quote, p.ExpectedAvg(), p.Unallocated)
+	for _, c := range r.Children {
+		status := "placed " + c.OrderID
+		switch {
+		case c.Err != nil:
+			status = "failed: " + c.Err.Error()
+		case c.Canceled:
+			status += " canceled"
+		case c.CancelErr != nil:
+			status += " open, cancel failed: " + c.CancelErr.Error()
+		}
+		fmt.Fprintf(w, "  %-6s %v @ limit %v expected %v filled %v avg %v %s\n",
+			c.Venue.Name(), c.Amount, c.LimitPrice, c.ExpectedAvg, c.Filled, c.AvgPrice, status)
+	}
+	for name, err := range p.Skipped {
+		fmt.Fprintf(w, "  %-6s skipped: %v\n", name, err)
+	}
+	for name, amount := range p.Dropped {
+		fmt.Fprintf(w, "  %-6s dropped %v below min child\n", name, amount)
+	}
+	fmt.Fprintf(w, "filled %v avg %v\n", r.Filled, r.AvgPrice())
+}
@@ -0,0 +1,345 @@
+package router
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go-exchange/fee"
+	"go-exchange/impact"
+	"go-exchange/order"
+	"go-exchange/venue"
+)
+
+// fakeVenue 内存中的交易所 下单后按限价立即成交
+type fakeVenue struct {
+	name     string
+	book     impact.Book
+	balances []venue.Balance
+	fail     bool
+	pending  bool // 下单后只成交一半 一直挂单
+	noCancel bool // 撤单失败
+
+	mu       sync.Mutex
+	placed   []float64 // 每笔下单的数量
+	limits   []float64
+	canceled []string
+}
+
+func (f *fakeVenue) Name() string                       { return f.name }
+func (f *fakeVenue) Balances() ([]venue.Balance, error) { return f.balances, nil }
+func (f *fakeVenue) OpenOrders() ([]venue.Order, error) { return nil, nil }
+func (f *fakeVenue) CancelOrder(venue.Order) error      { return nil }
+func (f *fakeVenue) OrderBook(base, quote string) (*impact.Book, error) {
+	b := f.book
+	return &b, nil
+}
+
+func (f *fakeVenue) MarketSell(base, quote string, amount float64) (string, error) {
+	return "", venue.ErrNoPair
+}
+
+func (f *fakeVenue) PlaceLimit(base, quote, side string, price, amount float64) (string, error) {
+	if f.fail {
+		return "", errTest
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.placed = append(f.placed, amount)
+	f.limits = append(f.limits, price)
+	return f.name + "-1", nil
+}
+
+func (f *fakeVenue) OrderStatus(base, quote, id string) (venue.Execution, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.pending && len(f.canceled) == 0 {
+		return venue.Execution{ID: id, Filled: f.placed[0] / 2, AvgPrice: f.limits[0]}, nil
+	}
+	return venue.Execution{ID: id, Filled: f.placed[0], AvgPrice: f.limits[0], Done: true}, nil
+}
+
+func (f *fakeVenue) CancelLimit(base, quote, id string) error {
+	if f.noCancel {
+		return errTest
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.canceled = append(f.canceled, id)
+	f.placed[0] /= 2
+	return nil
+}
+
+type testError string
+
+func (e testError) Error() string { return string(e) }
+
+const errTest = testError("rejected")
+
+func closeTo(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func usdt(amount float64) []venue.Balance {
+	return []venue.Balance{{Currency: "usdt", Available: amount}}
+}
+
+func TestPlanBuy(t *testing.T) {
+	a := &fakeVenue{name: "a", balances: usdt(1e6), book: impact.Book{
+		Asks: []impact.Level{{Price: 100, Amount: 1}, {Price: 103, Amount: 5}},
+	}}
+	b := &fakeVenue{name: "b", balances: usdt(1e6), book: impact.Book{
+		Asks: []impact.Level{{Price: 101, Amount: 2}, {Price: 102, Amount: 5}},
+	}}
+	fees := fee.NewModel()
+	fees.SetDefault("a", fee.Rate{Taker: 0.002})
+	fees.SetDefault("b", fee.Rate{Taker: 0.001})
+
+	plan, err := New(fees, a, b).Plan("ETH", "USDT", "buy", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 含手续费 a 100.2 < b 101.101 < b 102.102 < a 103.206
+	if len(plan.Children) != 2 || plan.Unallocated != 0 {
+		t.Fatalf("plan %+v", plan)
+	}
+	ca, cb := plan.Children[0], plan.Children[1]
+	if ca.Venue.Name() != "a" || !closeTo(ca.Amount, 1) || ca.LimitPrice != 100 {
+		t.Errorf("child a %+v", ca)
+	}
+	if cb.Venue.Name() != "b" || !closeTo(cb.Amount, 3) || cb.LimitPrice != 102 || cb.Levels != 2 {
+		t.Errorf("child b %+v", cb)
+	}
+	if !closeTo(cb.ExpectedAvg, (101*2+102)/3.0) || !closeTo(cb.Fee, (101*2+102)*0.001) {
+		t.Errorf("child b avg %v fee %v", cb.ExpectedAvg, cb.Fee)
+	}
+	want := (100*1.002 + (101*2+102)*1.001) / 4
+	if !closeTo(plan.ExpectedAvg(), want) {
+		t.Errorf("expected avg %v want %v", plan.ExpectedAvg(), want)
+	}
+}
+
+func TestPlanBalanceLimit(t *testing.T) {
+	a := &fakeVenue{name: "a", balances: usdt(150), book: impact.Book{
+		Asks: []impact.Level{{Price: 100, Amount: 10}},
+	}}
+	b := &fakeVenue{name: "b", balances: usdt(1e6), book: impact.Book{
+		Asks: []impact.Level{{Price: 110, Amount: 1}},
+	}}
+	plan, err := New(nil, a, b).Plan("eth", "usdt", "buy", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Children) != 2 || !closeTo(plan.Children[0].Amount, 1.5) || !closeTo(plan.Children[1].Amount, 1) {
+		t.Fatalf("children %+v", plan.Children)
+	}
+	if !closeTo(plan.Unallocated, 2.5) {
+		t.Errorf("unallocated %v", plan.Unallocated)
+	}
+}
+
+func TestPlanSell(t *testing.T) {
+	a := &fakeVenue{name: "a", balances: []venue.Balance{{Currency: "eth", Available: 1}}, book: impact.Book{
+		Bids: []impact.Level{{Price: 100, Amount: 5}},
+	}}
+	b := &fakeVenue{name: "b", balances: []venue.Balance{{Currency: "eth", Available: 5}}, book: impact.Book{
+		Bids: []impact.Level{{Price: 99, Amount: 5}, {Price: 98, Amount: 5}},
+	}}
+	r := New(nil, a, b)
+	r.MinChild = 50
+	r.PriceBuffer = 0.01
+	plan, err := r.Plan("eth", "usdt", "sell", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Children) != 2 || !closeTo(plan.Children[0].Amount, 1) || !closeTo(plan.Children[1].Amount, 2) {
+		t.Fatalf("children %+v", plan.Children)
+	}
+	if !closeTo(plan.Children[1].LimitPrice, 99*0.99) {
+		t.Errorf("limit %v", plan.Children[1].LimitPrice)
+	}
+
+	// a 只分到 0.4 低于最小金额 被排除 这部分改由 b 的下一档成交
+	a.balances[0].Available = 0.4
+	plan, err = r.Plan("eth", "usdt", "sell", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Children) != 1 || plan.Dropped["a"] != 0.4 || plan.Unallocated != 0 {
+		t.Fatalf("plan %+v", plan)
+	}
+	if c := plan.Children[0]; c.Venue != b || !closeTo(c.Amount, 3) {
+		t.Errorf("child %+v", c)
+	}
+}
+
+// preciseVenue 带下单规则的交易所
+type preciseVenue struct {
+	*fakeVenue
+	rules order.Rules
+}
+
+func (p *preciseVenue) PairRules(base, quote string) (order.Rules, error) {
+	return p.rules, nil
+}
+
+func TestPlanRounding(t *testing.T) {
+	a := &preciseVenue{
+		fakeVenue: &fakeVenue{name: "a", balances: usdt(1e6), book: impact.Book{
+			Asks: []impact.Level{{Price: 100.004, Amount: 1}, {Price: 100.016, Amount: 5}},
+		}},
+		rules: order.Rules{PriceDecimal: 2, AmountDecimal: 1},
+	}
+	plan, err := New(nil, a).Plan("eth", "usdt", "buy", 2.37)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := plan.Children[0]
+	// 买单限价向上取整 不低于最差档 数量向下取整
+	if c.LimitPrice != 100.02 || c.Amount != 2.3 || !closeTo(plan.Unallocated, 0.07) {
+		t.Fatalf("plan %+v child %+v", plan, c)
+	}
+	if !closeTo(c.ExpectedAvg, (100.004+1.3*100.016)/2.3) {
+		t.Errorf("avg %v", c.ExpectedAvg)
+	}
+
+	// 取整少下的 0.07 改由 b 成交
+	b := &fakeVenue{name: "b", balances: usdt(1e6), book: impact.Book{
+		Asks: []impact.Level{{Price: 101, Amount: 10}},
+	}}
+	plan, err = New(nil, a, b).Plan("eth", "usdt", "buy", 2.37)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Children) != 2 || plan.Children[0].Amount != 2.3 || !closeTo(plan.Children[1].Amount, 0.07) || plan.Unallocated != 0 {
+		t.Fatalf("plan %+v", plan)
+	}
+
+	// 限价加上 PriceBuffer 后余额不够 少下的数量也改由 b 成交
+	a.balances = usdt(203)
+	r := New(nil, a, b)
+	r.PriceBuffer = 0.01
+	plan, err = r.Plan("eth", "usdt", "buy", 2.37)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Children) != 2 || plan.Children[0].Amount != 2 || !closeTo(plan.Children[1].Amount, 0.37) || plan.Unallocated != 0 {
+		t.Fatalf("plan %+v children %+v", plan, plan.Children)
+	}
+
+	// 低于交易所最小下单量 排除后改由 b 成交
+	a.balances = usdt(1e6)
+	a.rules.MinAmount = 5
+	plan, err = New(nil, a, b).Plan("eth", "usdt", "buy", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Children) != 1 || plan.Children[0].Venue != b || plan.Dropped["a"] != 2 {
+		t.Fatalf("plan %+v", plan)
+	}
+}
+
+func TestPlanSkipsVenueWithoutFee(t *testing.T) {
+	a := &fakeVenue{name: "a", balances: usdt(1e6), book: impact.Book{
+		Asks: []impact.Level{{Price: 101, Amount: 10}},
+	}}
+	b := &fakeVenue{name: "b", balances: usdt(1e6), book: impact.Book{
+		Asks: []impact.Level{{Price: 100, Amount: 10}},
+	}}
+	fees := fee.NewModel()
+	fees.SetDefault("a", fee.Rate{Taker: 0.002})
+
+	// b 没有费率 不能当作零手续费而分到最多
+	plan, err := New(fees, a, b).Plan("eth", "usdt", "buy", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Children) != 1 || plan.Children[0].Venue != a || plan.Skipped["b"] != fee.ErrNoRate {
+		t.Fatalf("plan %+v", plan)
+	}
+}
+
+func TestPlanErrors(t *testing.T) {
+	if _, err := New(nil).Plan("eth", "usdt", "buy", 1); err != ErrNoVenue {
+		t.Errorf("got %v", err)
+	}
+	a := &fakeVenue{name: "a"}
+	if _, err := New(nil, a).Plan("eth", "usdt", "hold", 1); err == nil {
+		t.Error("want side error")
+	}
+	if _, err := New(nil, a).Plan("eth", "usdt", "buy", 0); err == nil {
+		t.Error("want amount error")
+	}
+}
+
+func TestRoute(t *testing.T) {
+	a := &fakeVenue{name: "a", balances: usdt(1e6), book: impact.Book{
+		Asks: []impact.Level{{Price: 100, Amount: 1}},
+	}}
+	b := &fakeVenue{name: "b", balances: usdt(1e6), book: impact.Book{
+		Asks: []impact.Level{{Price: 102, Amount: 1}},
+	}}
+	c := &fakeVenue{name: "c", balances: usdt(1e6), fail: true, book: impact.Book{
+		Asks: []impact.Level{{Price: 101, Amount: 1}},
+	}}
+	r := New(nil, a, b, c)
+	r.Wait = time.Second
+	r.Poll = time.Millisecond
+	report, err := r.Route("eth", "usdt", "buy", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.OK() || report.Children[2].Err != errTest {
+		t.Errorf("children %+v", report.Children)
+	}
+	if !closeTo(report.Filled, 2) || !closeTo(report.AvgPrice(), 101) {
+		t.Errorf("filled %v avg %v", report.Filled, report.AvgPrice())
+	}
+	if report.Children[0].OrderID != "a-1" || !report.Children[1].Done {
+		t.Errorf("children %+v", report.Children)
+	}
+
+	var buf bytes.Buffer
+	report.Write(&buf)
+	if !strings.Contains(buf.String(), "failed: rejected") || !strings.Contains(buf.String(), "filled 2") {
+		t.Errorf("report %s", buf.String())
+	}
+}
+
+func TestRouteCancelsAtDeadline(t *testing.T) {
+	a := &fakeVenue{name: "a", balances: usdt(1e6), pending: true, book: impact.Book{
+		Asks: []impact.Level{{Price: 100, Amount: 1}},
+	}}
+	b := &fakeVenue{name: "b", balances: usdt(1e6), pending: true, noCancel: true, book: impact.Book{
+		Asks: []impact.Level{{Price: 101, Amount: 1}},
+	}}
+	r := New(nil, a, b)
+	r.Wait = 5 * time.Millisecond
+	r.Poll = time.Millisecond
+	report, err := r.Route("eth", "usdt", "buy", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, cb := report.Children[0], report.Children[1]
+	if len(a.canceled) != 1 || !ca.Canceled || !ca.Done || !closeTo(ca.Filled, 0.5) {
+		t.Errorf("child a %+v", ca)
+	}
+	if cb.Canceled || cb.CancelErr != errTest || cb.Done {
+		t.Errorf("child b %+v", cb)
+	}
+	if open := report.Open(); len(open) != 1 || open[0].Venue != b {
+		t.Errorf("open %+v", open)
+	}
+	if !report.OK() || !closeTo(report.Filled, 1) {
+		t.Errorf("filled %v", report.Filled)
+	}
+
+	var buf bytes.Buffer
+	report.Write(&buf)
+	if !strings.Contains(buf.String(), "a-1 canceled") || !strings.Contains(buf.String(), "open, cancel failed") {
+		t.Errorf("report %s", buf.String())
+	}
+}
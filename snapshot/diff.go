@@ -0,0 +1,142 @@
+package snapshot
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"go-exchange/venue"
+)
+
+// Epsilon 小于该值的变化视为没有变化
+const Epsilon = 1e-9
+
+// Kind 事件类型
+type Kind string
+
+// 事件类型
+const (
+	KindFill       Kind = "fill"       // 成交引起的变化
+	KindDeposit    Kind = "deposit"    // 充值 或未被成交解释的增加
+	KindWithdrawal Kind = "withdrawal" // 提现 或未被成交解释的减少
+	KindUnexpected Kind = "unexpected" // 有成交的币种 但成交无法解释的变化
+)
+
+// Event 余额变化事件 Matched 为 true 表示与已知的成交或充提记录对上
+type Event struct {
+	Kind     Kind        `json:"kind"`
+	Venue    string      `json:"venue"`
+	Currency string      `json:"currency"`
+	Amount   float64     `json:"amount"` // 增加为正 减少为负
+	From     time.Time   `json:"from"`
+	To       time.Time   `json:"to"`
+	Matched  bool        `json:"matched"`
+	Fill     *venue.Fill `json:"fill,omitempty"`
+	Transfer *Transfer   `json:"transfer,omitempty"`
+}
+
+// Transfer 已知的充值或提现 Amount 充值为正 提现为负
+type Transfer struct {
+	Currency string    `json:"currency"`
+	Amount   float64   `json:"amount"`
+	Time     time.Time `json:"time"`
+}
+
+// Diff 两次快照之间每个币种总额的变化 只在可用与冻结之间转移不算变化
+func Diff(prev, cur *Snapshot) map[string]float64 {
+	before, after := prev.Totals(), cur.Totals()
+	res := make(map[string]float64)
+	for c, v := range after {
+		if d := v - before[c]; math.Abs(d) > Epsilon {
+			res[c] = d
+		}
+	}
+	for c, v := range before {
+		if _, ok := after[c]; !ok && math.Abs(v) > Epsilon {
+			res[c] = -v
+		}
+	}
+	return res
+}
+
+// FillDeltas 成交对各币种余额的影响 买入增加基准减少计价 卖出相反 手续费从手续费币种扣除
+func FillDeltas(f *venue.Fill) map[string]float64 {
+	res := make(map[string]float64, 3)
+	value := f.Price * f.Amount
+	if f.Side == "sell" {
+		res[f.Base] -= f.Amount
+		res[f.Quote] += value
+	} else {
+		res[f.Base] += f.Amount
+		res[f.Quote] -= value
+	}
+	if f.FeeCurrency != "" {
+		res[f.FeeCurrency] -= f.Fee
+	}
+	return res
+}
+
+// Classify 用已知成交和充提记录解释两次快照之间的变化
+// 每笔成交和充提各产生一个已匹配的事件 剩余的变化 币种有成交的为 KindUnexpected
+// 没有成交的按正负记为未匹配的 KindDeposit 或 KindWithdrawal
+func Classify(prev, cur *Snapshot, fills []venue.Fill, transfers []Transfer) []Event {
+	residual := Diff(prev, cur)
+	touched := make(map[string]bool)
+	events := make([]Event, 0)
+	base := Event{Venue: cur.Venue, From: prev.Time, To: cur.Time}
+
+	for i := range fills {
+		f := fills[i]
+		for c, d := range FillDeltas(&f) {
+			residual[c] -= d
+			touched[c] = true
+		}
+		e := base
+		e.Kind, e.Currency, e.Matched, e.Fill = KindFill, f.Base, true, &f
+		e.Amount = f.Amount
+		if f.Side == "sell" {
+			e.Amount = -f.Amount
+		}
+		events = append(events, e)
+	}
+	for i := range transfers {
+		t := transfers[i]
+		residual[t.Currency] -= t.Amount
+		e := base
+		e.Kind, e.Currency, e.Amount, e.Matched, e.Transfer = KindDeposit, t.Currency, t.Amount, true, &t
+		if t.Amount < 0 {
+			e.Kind = KindWithdrawal
+		}
+		events = append(events, e)
+	}
+
+	currencies := make([]string, 0, len(residual))
+	for c := range residual {
+		currencies = append(currencies, c)
+	}
+	sort.Strings(currencies)
+	for _, c := range currencies {
+		d := residual[c]
+		if math.Abs(d) <= tolerance(prev, cur, c) {
+			continue
+		}
+		e := base
+		e.Currency, e.Amount = c, d
+		switch {
+		case touched[c]:
+			e.Kind = KindUnexpected
+		case d > 0:
+			e.Kind = KindDeposit
+		default:
+			e.Kind = KindWithdrawal
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+// tolerance 成交金额按精度取整 允许余额的百万分之一的误差
+func tolerance(prev, cur *Snapshot, currency string) float64 {
+	v := math.Max(math.Abs(prev.Totals()[currency]), math.Abs(cur.Totals()[currency]))
+	return math.Max(Epsilon, v*1e-6)
+}
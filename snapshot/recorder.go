@@ -0,0 +1,145 @@
+package snapshot
+
+import (
+	"sync"
+	"time"
+
+	"go-exchange/batch"
+	"go-exchange/venue"
+)
+
+// TransferSource 已知充提记录的来源 可选
+type TransferSource interface {
+	Transfers(venueName string, from, to time.Time) ([]Transfer, error)
+}
+
+// Recorder 定期记录所有交易所的余额 与上一次快照比较并产生事件
+// 实现了 venue.FillHistory 的交易所用成交记录解释变化
+type Recorder struct {
+	Venues    []venue.Venue
+	Store     *Store
+	Transfers TransferSource // 可以为 nil
+	OnEvent   func(Event)    // 可以为 nil
+
+	now  func() time.Time
+	mu   sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewRecorder 新建记录器
+func NewRecorder(store *Store, venues ...venue.Venue) *Recorder {
+	return &Recorder{Venues: venues, Store: store, now: time.Now}
+}
+
+// RecordOnce 立即记录一次 返回本次产生的事件
+// 单个交易所失败不影响其他交易所 返回第一个错误
+// 事件写入之后才保存快照 查询成交或充提失败的交易所不保存快照 下次仍与上一次快照比较
+func (r *Recorder) RecordOnce() ([]Event, error) {
+	results := make([][]Event, len(r.Venues))
+	snaps := make([]*Snapshot, len(r.Venues))
+	errs := batch.Run(len(r.Venues), batch.Options{Concurrency: len(r.Venues)}, func(i int) error {
+		events, cur, err := r.record(r.Venues[i])
+		results[i], snaps[i] = events, cur
+		return err
+	})
+	events := make([]Event, 0)
+	for _, es := range results {
+		events = append(events, es...)
+	}
+	if err := r.Store.AppendEvents(events); err != nil {
+		return events, err
+	}
+	for _, cur := range snaps {
+		if cur == nil {
+			continue
+		}
+		if err := r.Store.Append(cur); err != nil {
+			return events, err
+		}
+	}
+	if r.OnEvent != nil {
+		for _, e := range events {
+			r.OnEvent(e)
+		}
+	}
+	return events, batch.First(errs)
+}
+
+// record 查询余额并与上一次快照比较 返回事件和待保存的快照 出错时都为 nil
+func (r *Recorder) record(v venue.Venue) ([]Event, *Snapshot, error) {
+	balances, err := v.Balances()
+	if err != nil {
+		return nil, nil, err
+	}
+	cur := &Snapshot{Time: r.now(), Venue: v.Name(), Balances: nonZero(balances)}
+	prev, err := r.Store.Last(v.Name())
+	if err != nil {
+		return nil, nil, err
+	}
+	if prev == nil {
+		return nil, cur, nil
+	}
+
+	var fills []venue.Fill
+	if h, ok := v.(venue.FillHistory); ok {
+		if fills, err = h.Fills(prev.Time, cur.Time); err != nil {
+			return nil, nil, err
+		}
+	}
+	var transfers []Transfer
+	if r.Transfers != nil {
+		if transfers, err = r.Transfers.Transfers(v.Name(), prev.Time, cur.Time); err != nil {
+			return nil, nil, err
+		}
+	}
+	return Classify(prev, cur, fills, transfers), cur, nil
+}
+
+func nonZero(balances []venue.Balance) []venue.Balance {
+	res := make([]venue.Balance, 0, len(balances))
+	for _, b := range balances {
+		if b.Total() != 0 {
+			res = append(res, b)
+		}
+	}
+	return res
+}
+
+// Start 立即记录一次 之后每隔 interval 记录 onError 可以为 nil
+// 只能查询最近成交的交易所 (如 gate 的 venue.GateFillWindow) interval 应小于其查询范围
+func (r *Recorder) Start(interval time.Duration, onError func(error)) {
+	r.Stop()
+	stop, done := make(chan struct{}), make(chan struct{})
+	r.mu.Lock()
+	r.stop, r.done = stop, done
+	r.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if _, err := r.RecordOnce(); err != nil && onError != nil {
+				onError(err)
+			}
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop 停止定时记录
+func (r *Recorder) Stop() {
+	r.mu.Lock()
+	stop, done := r.stop, r.done
+	r.stop, r.done = nil, nil
+	r.mu.Unlock()
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
@@ -0,0 +1,218 @@
+package snapshot
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-exchange/venue"
+)
+
+func snap(at int64, balances ...venue.Balance) *Snapshot {
+	return &Snapshot{Time: time.Unix(at, 0), Venue: "v", Balances: balances}
+}
+
+func kinds(events []Event) map[string]Kind {
+	res := make(map[string]Kind)
+	for _, e := range events {
+		if e.Kind != KindFill {
+			res[e.Currency] = e.Kind
+		}
+	}
+	return res
+}
+
+func TestClassify(t *testing.T) {
+	prev := snap(0,
+		venue.Balance{Currency: "eth", Available: 1},
+		venue.Balance{Currency: "usdt", Available: 1000},
+		venue.Balance{Currency: "bix", Available: 10},
+		venue.Balance{Currency: "btc", Available: 1},
+	)
+	cur := snap(60,
+		// 1 eth 挂单冻结 不算变化
+		venue.Balance{Currency: "eth", Available: 1, Frozen: 1},
+		venue.Balance{Currency: "usdt", Available: 790},
+		// 手续费 1 bix 另外丢了 2 bix
+		venue.Balance{Currency: "bix", Available: 7},
+		// 新充值
+		venue.Balance{Currency: "ft", Available: 50},
+	)
+	fills := []venue.Fill{
+		{Base: "eth", Quote: "usdt", Side: "buy", Price: 200, Amount: 1, Fee: 1, FeeCurrency: "bix"},
+	}
+	events := Classify(prev, cur, fills, nil)
+
+	if events[0].Kind != KindFill || !events[0].Matched || events[0].Amount != 1 {
+		t.Fatal(events[0])
+	}
+	k := kinds(events)
+	// usdt 多出 -10 无法解释 bix 少 2 btc 被提走 ft 充值
+	if len(k) != 4 || k["usdt"] != KindUnexpected || k["bix"] != KindUnexpected || k["btc"] != KindWithdrawal || k["ft"] != KindDeposit {
+		t.Fatal(k)
+	}
+
+	transfers := []Transfer{{Currency: "btc", Amount: -1}, {Currency: "ft", Amount: 50}}
+	events = Classify(prev, cur, fills, transfers)
+	k = kinds(events)
+	if k["btc"] != KindWithdrawal || k["ft"] != KindDeposit {
+		t.Fatal(k)
+	}
+	for _, e := range events {
+		if (e.Currency == "btc" || e.Currency == "ft") && !e.Matched {
+			t.Fatal("transfer not matched", e)
+		}
+	}
+}
+
+type fakeVenue struct {
+	balances []venue.Balance
+	fills    []venue.Fill
+	fillsErr error
+}
+
+func (f *fakeVenue) Name() string                                      { return "fake" }
+func (f *fakeVenue) Balances() ([]venue.Balance, error)                { return f.balances, nil }
+func (f *fakeVenue) OpenOrders() ([]venue.Order, error)                { return nil, nil }
+func (f *fakeVenue) CancelOrder(o venue.Order) error                   { return nil }
+func (f *fakeVenue) MarketSell(b, q string, a float64) (string, error) { return "", nil }
+func (f *fakeVenue) Fills(from, to time.Time) ([]venue.Fill, error)    { return f.fills, f.fillsErr }
+
+func TestRecorder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := &fakeVenue{balances: []venue.Balance{{Currency: "usdt", Available: 100}, {Currency: "eth", Available: 0}}}
+	r := NewRecorder(store, v)
+	clock := time.Unix(1000, 0)
+	r.now = func() time.Time { return clock }
+
+	if events, err := r.RecordOnce(); err != nil || len(events) != 0 {
+		t.Fatal(events, err)
+	}
+
+	// 买入 0.1 eth 花费 20 usdt 手续费 0.001 eth
+	clock = clock.Add(time.Minute)
+	v.balances = []venue.Balance{{Currency: "usdt", Available: 80}, {Currency: "eth", Available: 0.099}}
+	v.fills = []venue.Fill{{Base: "eth", Quote: "usdt", Side: "buy", Price: 200, Amount: 0.1, Fee: 0.001, FeeCurrency: "eth"}}
+	var seen []Event
+	r.OnEvent = func(e Event) { seen = append(seen, e) }
+	events, err := r.RecordOnce()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Kind != KindFill || len(seen) != 1 {
+		t.Fatal(events)
+	}
+
+	// 中断留下的半行被丢弃 之后仍可追加
+	f, _ := os.OpenFile(filepath.Join(dir, "fake.jsonl"), os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString(`{"time":"2018`)
+	f.Close()
+	snaps, err := store.Snapshots("fake")
+	if err != nil || len(snaps) != 2 || len(snaps[0].Balances) != 1 {
+		t.Fatal(snaps, err)
+	}
+	clock = clock.Add(time.Minute)
+	v.fills = nil
+	v.balances = append(v.balances, venue.Balance{Currency: "ft", Available: 5})
+	if events, err := r.RecordOnce(); err != nil || len(events) != 1 || events[0].Kind != KindDeposit {
+		t.Fatal(events, err)
+	}
+	if snaps, _ := store.Snapshots("fake"); len(snaps) != 3 {
+		t.Fatal(snaps)
+	}
+	if all, err := store.Events(); err != nil || len(all) != 2 {
+		t.Fatal(all, err)
+	}
+}
+
+func TestRecorderFillsError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := &fakeVenue{balances: []venue.Balance{{Currency: "usdt", Available: 100}}}
+	r := NewRecorder(store, v)
+	clock := time.Unix(1000, 0)
+	r.now = func() time.Time { return clock }
+	if _, err := r.RecordOnce(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 查询成交失败 不保存快照
+	clock = clock.Add(time.Minute)
+	v.balances = []venue.Balance{{Currency: "usdt", Available: 150}}
+	v.fillsErr = errors.New("timeout")
+	if events, err := r.RecordOnce(); err == nil || len(events) != 0 {
+		t.Fatal(events, err)
+	}
+	if snaps, _ := store.Snapshots("fake"); len(snaps) != 1 {
+		t.Fatal(snaps)
+	}
+
+	// 恢复后仍与第一次快照比较 充值不会丢失
+	clock = clock.Add(time.Minute)
+	v.fillsErr = nil
+	events, err := r.RecordOnce()
+	if err != nil || len(events) != 1 || events[0].Kind != KindDeposit || events[0].Currency != "usdt" {
+		t.Fatal(events, err)
+	}
+	if snaps, _ := store.Snapshots("fake"); len(snaps) != 2 {
+		t.Fatal(snaps)
+	}
+}
+
+func TestStoreLast(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last, err := store.Last("fake"); err != nil || last != nil {
+		t.Fatal(last, err)
+	}
+	for i := 1; i <= 3; i++ {
+		if err := store.Append(&Snapshot{Time: time.Unix(int64(i), 0), Venue: "fake"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if last, err := store.Last("fake"); err != nil || last.Time.Unix() != 3 {
+		t.Fatal(last, err)
+	}
+
+	// 新的 Store 首次从文件读取 之后不再读文件
+	store, err = NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last, err := store.Last("fake"); err != nil || last.Time.Unix() != 3 {
+		t.Fatal(last, err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "fake.jsonl"), []byte("not json\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if last, err := store.Last("fake"); err != nil || last.Time.Unix() != 3 {
+		t.Fatal("last snapshot should be cached", last, err)
+	}
+}
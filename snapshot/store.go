@@ -0,0 +1,201 @@
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go-exchange/venue"
+)
+
+// Snapshot 某一时刻单个交易所的余额
+type Snapshot struct {
+	Time     time.Time       `json:"time"`
+	Venue    string          `json:"venue"`
+	Balances []venue.Balance `json:"balances"`
+}
+
+// Totals 每个币种的可用加冻结
+func (s *Snapshot) Totals() map[string]float64 {
+	res := make(map[string]float64, len(s.Balances))
+	for _, b := range s.Balances {
+		res[b.Currency] += b.Total()
+	}
+	return res
+}
+
+// Store 快照和事件文件 每个交易所一个 Dir/venue.jsonl 事件写入 Dir/events.jsonl
+// 每行一个 JSON 只追加不改写 写到一半中断的最后一行在读取时丢弃
+// 每个交易所的最近一次快照首次读取后缓存在内存中 文件只能由这个 Store 写入
+type Store struct {
+	Dir string
+
+	mu   sync.Mutex
+	last map[string]*Snapshot
+}
+
+// NewStore 新建存储 目录不存在时创建
+func NewStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &Store{Dir: dir, last: make(map[string]*Snapshot)}, nil
+}
+
+func (s *Store) snapshotPath(venueName string) string {
+	return filepath.Join(s.Dir, venueName+".jsonl")
+}
+
+func (s *Store) eventPath() string {
+	return filepath.Join(s.Dir, "events.jsonl")
+}
+
+// Append 追加快照
+func (s *Store) Append(snap *Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := appendLine(s.snapshotPath(snap.Venue), snap); err != nil {
+		return err
+	}
+	if s.last == nil {
+		s.last = make(map[string]*Snapshot)
+	}
+	cp := *snap
+	s.last[snap.Venue] = &cp
+	return nil
+}
+
+// AppendEvents 追加事件
+func (s *Store) AppendEvents(events []Event) error {
+	for i := range events {
+		if err := appendLine(s.eventPath(), &events[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Snapshots 交易所的所有快照 按写入顺序
+func (s *Store) Snapshots(venueName string) ([]Snapshot, error) {
+	res := make([]Snapshot, 0)
+	err := readLines(s.snapshotPath(venueName), func(line []byte) error {
+		var snap Snapshot
+		if err := json.Unmarshal(line, &snap); err != nil {
+			return err
+		}
+		res = append(res, snap)
+		return nil
+	})
+	return res, err
+}
+
+// Last 交易所最近一次快照 没有时返回 nil 只在首次调用时读取文件 返回值不能修改
+func (s *Store) Last(venueName string) (*Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if snap, ok := s.last[venueName]; ok {
+		return snap, nil
+	}
+	snaps, err := s.Snapshots(venueName)
+	if err != nil {
+		return nil, err
+	}
+	var snap *Snapshot
+	if len(snaps) > 0 {
+		snap = &snaps[len(snaps)-1]
+	}
+	if s.last == nil {
+		s.last = make(map[string]*Snapshot)
+	}
+	s.last[venueName] = snap
+	return snap, nil
+}
+
+// Events 所有事件 按写入顺序
+func (s *Store) Events() ([]Event, error) {
+	res := make([]Event, 0)
+	err := readLines(s.eventPath(), func(line []byte) error {
+		var e Event
+		if err := json.Unmarshal(line, &e); err != nil {
+			return err
+		}
+		res = append(res, e)
+		return nil
+	})
+	return res, err
+}
+
+func appendLine(path string, v interface{}) error {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	end, err := completeSize(f)
+	if err != nil {
+		return err
+	}
+	// 上次中断留下的不完整行 先截掉
+	if err := f.Truncate(end); err != nil {
+		return err
+	}
+	if _, err := f.WriteAt(append(bs, '\n'), end); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// completeSize 文件中完整行的总长度 只读最后一个字节 不完整时才往回找换行
+func completeSize(f *os.File) (int64, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	size := info.Size()
+	if size == 0 {
+		return 0, nil
+	}
+	last := make([]byte, 1)
+	if _, err := f.ReadAt(last, size-1); err != nil {
+		return 0, err
+	}
+	if last[0] == '\n' {
+		return size, nil
+	}
+	data := make([]byte, size)
+	if _, err := f.ReadAt(data, 0); err != nil {
+		return 0, err
+	}
+	return int64(bytes.LastIndexByte(data, '\n') + 1), nil
+}
+
+func readLines(path string, fn func(line []byte) error) error {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if n := bytes.LastIndexByte(data, '\n'); n != len(data)-1 {
+		data = data[:n+1]
+	}
+	for i, line := range bytes.Split(data, []byte{'\n'}) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		if err := fn(line); err != nil {
+			return fmt.Errorf("%s line %d: %v", path, i+1, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,292 @@
+package venue
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-exchange/batch"
+	"go-exchange/bibox"
+	"go-exchange/impact"
+	"go-exchange/order"
+)
+
+// Bibox bibox 普通账户
+// bibox 没有精度接口 交易对规则需要用 Builder.Set 手动设置 交易对如 BIX_ETH
+type Bibox struct {
+	Service *bibox.BiboxService
+	Builder *order.Builder
+
+	mu    sync.Mutex
+	pairs map[string]bool
+}
+
+// NewBibox 新建 bibox 适配器
+func NewBibox(bs *bibox.BiboxService) *Bibox {
+	return &Bibox{Service: bs, Builder: order.NewBuilder(nil)}
+}
+
+// Name 交易所名称
+func (b *Bibox) Name() string {
+	return "bibox"
+}
+
+// Balances 余额
+func (b *Bibox) Balances() ([]Balance, error) {
+	res, err := b.Service.GetAssets()
+	if err != nil {
+		return nil, err
+	}
+	balances := make([]Balance, 0, len(res.Result.AssetsList))
+	for _, a := range res.Result.AssetsList {
+		available, err := parseFloat(a.Balance)
+		if err != nil {
+			return nil, err
+		}
+		frozen, err := parseFloat(a.Freeze)
+		if err != nil {
+			return nil, err
+		}
+		balances = append(balances, Balance{Currency: strings.ToLower(a.CoinSymbol), Available: available, Frozen: frozen})
+	}
+	return balances, nil
+}
+
+// OpenOrders 普通账户的所有挂单
+func (b *Bibox) OpenOrders() ([]Order, error) {
+	accountType := bibox.AccountTypeCommon
+	it := b.Service.NewCurrentPendingIterator(&bibox.PendingBody{AccountType: &accountType, Page: 1, Size: 50})
+	res := make([]Order, 0)
+	for it.Next() {
+		item := it.Item()
+		price, err := parseFloat(item.Price)
+		if err != nil {
+			return res, err
+		}
+		amount, err := parseFloat(item.Amount)
+		if err != nil {
+			return res, err
+		}
+		filled, err := parseFloat(item.DealAmount)
+		if err != nil {
+			return res, err
+		}
+		side := "buy"
+		if item.OrderSide == bibox.OrderSideSell {
+			side = "sell"
+		}
+		res = append(res, Order{
+			Venue:  b.Name(),
+			ID:     strconv.Itoa(item.ID),
+			Pair:   item.CoinSymbol + "_" + item.CurrencySymbol,
+			Side:   side,
+			Price:  price,
+			Amount: amount,
+			Filled: filled,
+		})
+	}
+	return res, it.Err()
+}
+
+// CancelOrder 撤单
+func (b *Bibox) CancelOrder(o Order) error {
+	id, err := strconv.ParseUint(o.ID, 10, 64)
+	if err != nil {
+		return err
+	}
+	res, err := b.Service.CancelTrade(id)
+	if err != nil {
+		return err
+	}
+	if res.Error != nil {
+		return fmt.Errorf("cancel %s: %s", o.ID, res.Error.Msg)
+	}
+	return nil
+}
+
+// MarketSell 市价卖出 设置了交易对规则时数量按精度向下取整 否则原样提交
+func (b *Bibox) MarketSell(base, quote string, amount float64) (string, error) {
+	pair := strings.ToUpper(base + "_" + quote)
+	if _, ok := b.Builder.Rules(pair); ok {
+		rounded, _, err := b.Builder.Amount(pair, amount)
+		if err != nil {
+			return "", err
+		}
+		amount = rounded
+	}
+	res, err := b.Service.Trade(&bibox.TradeBody{
+		Pair:        pair,
+		AccountType: bibox.AccountTypeCommon,
+		OrderType:   bibox.OrderTypeMarket,
+		OrderSide:   bibox.OrderSideSell,
+		Amount:      amount,
+	})
+	if err != nil {
+		return "", err
+	}
+	if res.Error != nil {
+		return "", fmt.Errorf("sell %s: %s", base, res.Error.Msg)
+	}
+	return strconv.FormatUint(res.Result, 10), nil
+}
+
+// Pairs 交易对列表 首次调用时查询并缓存
+func (b *Bibox) Pairs() (map[string]bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.pairs == nil {
+		res, err := b.Service.GetPairList()
+		if err != nil {
+			return nil, err
+		}
+		b.pairs = make(map[string]bool, len(res.Result))
+		for _, p := range res.Result {
+			b.pairs[p.Pair] = true
+		}
+	}
+	return b.pairs, nil
+}
+
+// LastPrice 最新成交价
+func (b *Bibox) LastPrice(base, quote string) (float64, error) {
+	pairs, err := b.Pairs()
+	if err != nil {
+		return 0, err
+	}
+	pair := strings.ToUpper(base + "_" + quote)
+	if !pairs[pair] {
+		return 0, ErrNoPair
+	}
+	res, err := b.Service.GetTicker(pair)
+	if err != nil {
+		return 0, err
+	}
+	return parseFloat(res.Result.Last)
+}
+
+// Fills 普通账户的成交记录 从最新一页往回翻 直到早于 from 翻页间隔 bibox.DefaultPageInterval
+func (b *Bibox) Fills(from, to time.Time) ([]Fill, error) {
+	accountType := bibox.AccountTypeCommon
+	body := &bibox.PendingBody{AccountType: &accountType, Page: 1, Size: 50}
+	fromMs, toMs := uint64(from.UnixNano()/1e6), uint64(to.UnixNano()/1e6)
+	pacer := batch.Pacer{Interval: bibox.DefaultPageInterval}
+	res := make([]Fill, 0)
+	for {
+		pacer.Wait()
+		page, err := b.Service.FillHistory(body)
+		if err != nil {
+			return res, err
+		}
+		items := page.Result.Items
+		older := false
+		for _, item := range items {
+			if item.CreatedAt < fromMs {
+				older = true
+				continue
+			}
+			if item.CreatedAt > toMs {
+				continue
+			}
+			f, err := biboxFill(item)
+			if err != nil {
+				return res, err
+			}
+			res = append(res, f)
+		}
+		if older || len(items) < body.Size {
+			break
+		}
+		body.Page++
+	}
+	sortFills(res)
+	return res, nil
+}
+
+func biboxFill(item bibox.Fill) (Fill, error) {
+	f := Fill{
+		Venue:       "bibox",
+		ID:          strconv.FormatUint(item.ID, 10),
+		OrderID:     strconv.FormatUint(item.RelayID, 10),
+		Base:        strings.ToLower(item.CoinSymbol),
+		Quote:       strings.ToLower(item.CurrencySymbol),
+		Side:        "buy",
+		FeeCurrency: strings.ToLower(item.FeeSymbol),
+		Time:        time.Unix(int64(item.CreatedAt/1e3), int64(item.CreatedAt%1e3)*1e6),
+	}
+	if item.OrderSide == bibox.OrderSideSell {
+		f.Side = "sell"
+	}
+	var err error
+	if f.Price, err = parseFloat(item.Price); err != nil {
+		return f, err
+	}
+	if f.Amount, err = parseFloat(item.Amount); err != nil {
+		return f, err
+	}
+	if f.Fee, err = parseFloat(item.Fee); err != nil {
+		return f, err
+	}
+	return f, nil
+}
+
+// OrderBook 50 档深度
+func (b *Bibox) OrderBook(base, quote string) (*impact.Book, error) {
+	depth, err := b.Service.GetDepth(strings.ToUpper(base+"_"+quote), 50)
+	if err != nil {
+		return nil, err
+	}
+	return impact.FromBibox(depth)
+}
+
+// PairRules Builder 中设置的交易对规则 没有时返回 order.ErrUnknownPair
+func (b *Bibox) PairRules(base, quote string) (order.Rules, error) {
+	r, ok := b.Builder.Rules(strings.ToUpper(base + "_" + quote))
+	if !ok {
+		return r, order.ErrUnknownPair
+	}
+	return r, nil
+}
+
+// PlaceLimit 普通账户下限价单 价格和数量按 Builder 中的交易对规则取整 没有规则时返回 order.ErrUnknownPair
+func (b *Bibox) PlaceLimit(base, quote, side string, price, amount float64) (string, error) {
+	o, err := b.Builder.Limit(strings.ToUpper(base+"_"+quote), order.Side(side), price, amount)
+	if err != nil {
+		return "", err
+	}
+	res, err := b.Service.Trade(o.Bibox(bibox.AccountTypeCommon))
+	if err != nil {
+		return "", err
+	}
+	if res.Error != nil {
+		return "", fmt.Errorf("%s %s: %s", side, base, res.Error.Msg)
+	}
+	return strconv.FormatUint(res.Result, 10), nil
+}
+
+// CancelLimit 撤销 PlaceLimit 下的订单
+func (b *Bibox) CancelLimit(base, quote, id string) error {
+	return b.CancelOrder(Order{ID: id, Pair: strings.ToUpper(base + "_" + quote)})
+}
+
+// OrderStatus 订单成交情况
+func (b *Bibox) OrderStatus(base, quote, id string) (Execution, error) {
+	orderID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return Execution{}, err
+	}
+	res, err := b.Service.GetOrder(orderID, bibox.AccountTypeCommon)
+	if err != nil {
+		return Execution{}, err
+	}
+	o := res.Result
+	e := Execution{ID: id, Done: o.Status.Finished()}
+	if e.Filled, err = parseFloat(o.DealAmount); err != nil {
+		return e, err
+	}
+	if e.AvgPrice, err = parseFloat(o.DealPrice); err != nil {
+		return e, err
+	}
+	return e, nil
+}
@@ -0,0 +1,94 @@
+package venue
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-exchange/bibox"
+	"go-exchange/order"
+)
+
+func TestBiboxPlaceLimit(t *testing.T) {
+	var sent map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var params struct {
+			Cmds string `json:"cmds"`
+		}
+		json.NewDecoder(r.Body).Decode(&params)
+		var cmds []struct {
+			Body map[string]interface{} `json:"body"`
+		}
+		json.Unmarshal([]byte(params.Cmds), &cmds)
+		sent = cmds[0].Body
+		fmt.Fprint(w, `{"result":[{"result":42,"cmd":"orderpending/trade","index":1}]}`)
+	}))
+	defer server.Close()
+
+	bs, _ := bibox.NewBiboxService(server.URL+"/", "key", "secret")
+	b := NewBibox(bs)
+	if _, err := b.PlaceLimit("bix", "eth", "buy", 0.0012345, 10.56789); err != order.ErrUnknownPair {
+		t.Fatal("pair without rules should be rejected", err)
+	}
+	b.Builder.Set(order.Rules{Pair: "BIX_ETH", PriceDecimal: 6, AmountDecimal: 2})
+	for _, side := range []string{"SELL", "Sell", "seel", ""} {
+		if _, err := b.PlaceLimit("bix", "eth", side, 0.0012345, 10.56789); err == nil {
+			t.Fatalf("side %q should be rejected", side)
+		}
+	}
+	if sent != nil {
+		t.Fatal("rejected orders must not be sent", sent)
+	}
+
+	id, err := b.PlaceLimit("bix", "eth", "sell", 0.0012345, 10.56789)
+	if err != nil || id != "42" {
+		t.Fatal(id, err)
+	}
+	if sent["pair"] != "BIX_ETH" || sent["order_side"] != float64(bibox.OrderSideSell) || sent["price"] != 0.001235 || sent["amount"] != 10.56 {
+		t.Fatal(sent)
+	}
+}
+
+func TestBiboxFillsPaced(t *testing.T) {
+	var pages []float64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var params struct {
+			Cmds string `json:"cmds"`
+		}
+		json.NewDecoder(r.Body).Decode(&params)
+		var cmds []struct {
+			Body map[string]interface{} `json:"body"`
+		}
+		json.Unmarshal([]byte(params.Cmds), &cmds)
+		page := cmds[0].Body["page"].(float64)
+		pages = append(pages, page)
+		items := make([]string, 0, 50)
+		n := 50
+		if page > 1 {
+			n = 10
+		}
+		for i := 0; i < n; i++ {
+			items = append(items, fmt.Sprintf(`{"id":%d,"createdAt":%d,"coin_symbol":"BIX","currency_symbol":"ETH","order_side":2,"price":"1","amount":"1","fee":"0"}`, int(page)*100+i, 2000000-int(page)*1000-i))
+		}
+		fmt.Fprintf(w, `{"result":[{"result":{"count":60,"page":%d,"items":[%s]},"cmd":"orderpending/orderHistoryList","index":1}]}`, int(page), strings.Join(items, ","))
+	}))
+	defer server.Close()
+
+	bs, _ := bibox.NewBiboxService(server.URL+"/", "key", "secret")
+	b := NewBibox(bs)
+	start := time.Now()
+	fills, err := b.Fills(time.Unix(1000, 0), time.Unix(3000, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fills) != 60 || len(pages) != 2 || fills[0].Side != "sell" {
+		t.Fatal(len(fills), pages)
+	}
+	if elapsed := time.Since(start); elapsed < bibox.DefaultPageInterval {
+		t.Fatal("pages should be paced", elapsed)
+	}
+}
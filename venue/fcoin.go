@@ -0,0 +1,296 @@
+package venue
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go-exchange/batch"
+	"go-exchange/fcoin"
+	"go-exchange/impact"
+	"go-exchange/order"
+)
+
+// DefaultFcoinFillLookback Fills 默认查询 from 之前这段时间内创建的订单
+const DefaultFcoinFillLookback = 7 * 24 * time.Hour
+
+// Fcoin fcoin 现货账户
+type Fcoin struct {
+	Service      *fcoin.FcoinService
+	TradeSymbols []string      // fcoin 只能按交易对查询订单 Fills 查询这些交易对
+	FillLookback time.Duration // Fills 查询 from 之前这段时间内创建的订单 更早创建的订单的成交会缺失
+
+	mu      sync.Mutex
+	symbols []fcoin.Symbol
+}
+
+// NewFcoin 新建 fcoin 适配器
+func NewFcoin(fs *fcoin.FcoinService) *Fcoin {
+	return &Fcoin{Service: fs, FillLookback: DefaultFcoinFillLookback}
+}
+
+// Name 交易所名称
+func (f *Fcoin) Name() string {
+	return "fcoin"
+}
+
+// Symbols 交易对列表 首次调用时查询并缓存
+func (f *Fcoin) Symbols() ([]fcoin.Symbol, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.symbols == nil {
+		symbols, err := f.Service.GetSymbols()
+		if err != nil {
+			return nil, err
+		}
+		f.symbols = symbols
+	}
+	return f.symbols, nil
+}
+
+// Balances 现货余额
+func (f *Fcoin) Balances() ([]Balance, error) {
+	list, err := f.Service.GetAccountBalance()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]Balance, 0, len(list))
+	for _, b := range list {
+		available, err := parseFloat(b.Available)
+		if err != nil {
+			return nil, err
+		}
+		frozen, err := parseFloat(b.Frozen)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, Balance{Currency: strings.ToLower(b.Currency), Available: available, Frozen: frozen})
+	}
+	return res, nil
+}
+
+// OpenOrders fcoin 只能按交易对查询订单 只查询基准或计价货币有冻结余额的交易对
+func (f *Fcoin) OpenOrders() ([]Order, error) {
+	balances, err := f.Balances()
+	if err != nil {
+		return nil, err
+	}
+	frozen := make(map[string]bool)
+	for _, b := range balances {
+		if b.Frozen > 0 {
+			frozen[b.Currency] = true
+		}
+	}
+	if len(frozen) == 0 {
+		return nil, nil
+	}
+	symbols, err := f.Symbols()
+	if err != nil {
+		return nil, err
+	}
+
+	active := []fcoin.OrderState{fcoin.OrderSubmitted, fcoin.OrderPartialFilled}
+	res := make([]Order, 0)
+	for _, s := range symbols {
+		if !frozen[s.BaseCurrency] && !frozen[s.QuoteCurrency] {
+			continue
+		}
+		it := f.Service.NewOrderIterator(s.Name, active, 100)
+		for it.Next() {
+			o := it.Order()
+			res = append(res, Order{
+				Venue:  f.Name(),
+				ID:     o.ID,
+				Pair:   o.Symbol,
+				Side:   o.Side,
+				Price:  o.Price,
+				Amount: o.Amount,
+				Filled: o.FilledAmount,
+			})
+		}
+		if err := it.Err(); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// CancelOrder 撤单
+func (f *Fcoin) CancelOrder(o Order) error {
+	_, err := f.Service.CancelOrder(o.ID)
+	return err
+}
+
+// MarketSell 市价卖出 数量按交易对精度向下取整
+func (f *Fcoin) MarketSell(base, quote string, amount float64) (string, error) {
+	symbols, err := f.Symbols()
+	if err != nil {
+		return "", err
+	}
+	symbol := strings.ToLower(base + quote)
+	rules := order.FcoinRules(symbols)
+	if _, ok := rules[symbol]; !ok {
+		return "", ErrNoPair
+	}
+	_, text, err := order.NewBuilder(rules).Amount(symbol, amount)
+	if err != nil {
+		return "", err
+	}
+	return f.Service.CreateOrder(symbol, fcoin.SideSell, "market", "", text)
+}
+
+// LastPrice 最新成交价
+func (f *Fcoin) LastPrice(base, quote string) (float64, error) {
+	symbols, err := f.Symbols()
+	if err != nil {
+		return 0, err
+	}
+	symbol := strings.ToLower(base + quote)
+	if _, ok := order.FcoinRules(symbols)[symbol]; !ok {
+		return 0, ErrNoPair
+	}
+	t, err := f.Service.GetMarketTicker(symbol)
+	if err != nil {
+		return 0, err
+	}
+	return t.Ticker.Last, nil
+}
+
+// OrderFills 订单的成交记录 fcoin 买入手续费为基准货币 卖出为计价货币
+func (f *Fcoin) OrderFills(o *fcoin.OrderInformation) ([]Fill, error) {
+	symbols, err := f.Symbols()
+	if err != nil {
+		return nil, err
+	}
+	var symbol *fcoin.Symbol
+	for i := range symbols {
+		if symbols[i].Name == o.Symbol {
+			symbol = &symbols[i]
+		}
+	}
+	if symbol == nil {
+		return nil, ErrNoPair
+	}
+	matches, err := f.Service.OrderMatchResult(o.ID)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]Fill, 0, len(matches))
+	for i, m := range matches {
+		fill := Fill{
+			Venue:       f.Name(),
+			ID:          o.ID + "-" + strconv.Itoa(i),
+			OrderID:     o.ID,
+			Base:        symbol.BaseCurrency,
+			Quote:       symbol.QuoteCurrency,
+			Side:        m.Side,
+			FeeCurrency: symbol.BaseCurrency,
+			Time:        time.Unix(int64(m.CreatedAt/1e3), int64(m.CreatedAt%1e3)*1e6),
+		}
+		if m.Side == fcoin.SideSell {
+			fill.FeeCurrency = symbol.QuoteCurrency
+		}
+		if fill.Price, err = parseFloat(m.Price); err != nil {
+			return nil, err
+		}
+		if fill.Amount, err = parseFloat(m.FilledAmount); err != nil {
+			return nil, err
+		}
+		if fill.Fee, err = parseFloat(m.FillFees); err != nil {
+			return nil, err
+		}
+		res = append(res, fill)
+	}
+	sortFills(res)
+	return res, nil
+}
+
+// Fills TradeSymbols 中各交易对 [from, to] 内的成交 没有设置 TradeSymbols 时没有成交
+// 查询 from 之前 FillLookback 到 to 之间创建且有成交的订单 再逐个查询成交记录
+func (f *Fcoin) Fills(from, to time.Time) ([]Fill, error) {
+	states := []fcoin.OrderState{fcoin.OrderPartialFilled, fcoin.OrderPendingCancel, fcoin.OrderPartialCanceled, fcoin.OrderFilled}
+	pacer := batch.Pacer{Interval: fcoin.DefaultPageInterval}
+	res := make([]Fill, 0)
+	for _, symbol := range f.TradeSymbols {
+		orders, err := f.Service.NewOrderIterator(symbol, states, fcoin.MaxOrderLimit).Collect(from.Add(-f.FillLookback), to)
+		if err != nil {
+			return res, err
+		}
+		for i := range orders {
+			if orders[i].FilledAmount == 0 {
+				continue
+			}
+			pacer.Wait()
+			fills, err := f.OrderFills(&orders[i])
+			if err != nil {
+				return res, err
+			}
+			for _, fill := range fills {
+				if !fill.Time.Before(from) && !fill.Time.After(to) {
+					res = append(res, fill)
+				}
+			}
+		}
+	}
+	sortFills(res)
+	return res, nil
+}
+
+// OrderBook 20 档深度
+func (f *Fcoin) OrderBook(base, quote string) (*impact.Book, error) {
+	depth, err := f.Service.GetMarketDepth("L20", strings.ToLower(base+quote))
+	if err != nil {
+		return nil, err
+	}
+	return impact.FromFcoin(depth)
+}
+
+// PairRules 交易对下单规则
+func (f *Fcoin) PairRules(base, quote string) (order.Rules, error) {
+	symbols, err := f.Symbols()
+	if err != nil {
+		return order.Rules{}, err
+	}
+	r, ok := order.FcoinRules(symbols)[strings.ToLower(base+quote)]
+	if !ok {
+		return r, ErrNoPair
+	}
+	return r, nil
+}
+
+// PlaceLimit 下限价单
+func (f *Fcoin) PlaceLimit(base, quote, side string, price, amount float64) (string, error) {
+	symbols, err := f.Symbols()
+	if err != nil {
+		return "", err
+	}
+	symbol := strings.ToLower(base + quote)
+	rules := order.FcoinRules(symbols)
+	if _, ok := rules[symbol]; !ok {
+		return "", ErrNoPair
+	}
+	o, err := order.NewBuilder(rules).Limit(symbol, order.Side(side), price, amount)
+	if err != nil {
+		return "", err
+	}
+	return f.Service.CreateOrder(o.Fcoin())
+}
+
+// CancelLimit 撤销 PlaceLimit 下的订单
+func (f *Fcoin) CancelLimit(base, quote, id string) error {
+	return f.CancelOrder(Order{ID: id, Pair: strings.ToLower(base + quote)})
+}
+
+// OrderStatus 订单成交情况
+func (f *Fcoin) OrderStatus(base, quote, id string) (Execution, error) {
+	o, err := f.Service.GetOrderByID(id)
+	if err != nil {
+		return Execution{}, err
+	}
+	e := Execution{ID: id, Filled: o.FilledAmount, Done: o.State.Terminal()}
+	if o.FilledAmount > 0 {
+		e.AvgPrice = o.ExecutedValue / o.FilledAmount
+	}
+	return e, nil
+}
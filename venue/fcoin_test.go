@@ -0,0 +1,66 @@
+package venue
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-exchange/fcoin"
+)
+
+func TestFcoinFills(t *testing.T) {
+	matched := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/public/symbols":
+			fmt.Fprint(w, `{"status":0,"data":[{"name":"btcusdt","base_currency":"btc","quote_currency":"usdt"}]}`)
+		case "/v2/orders":
+			if r.URL.Query().Get("symbol") != "btcusdt" {
+				t.Error("symbol", r.URL.Query())
+			}
+			// b 在 from 之前创建 但在 from 之后才成交 c 没有成交
+			fmt.Fprint(w, `{"status":0,"data":[
+				{"id":"c","symbol":"btcusdt","side":"buy","state":"canceled","filled_amount":"0","created_at":1600000},
+				{"id":"a","symbol":"btcusdt","side":"buy","state":"filled","filled_amount":"1","created_at":1500000},
+				{"id":"b","symbol":"btcusdt","side":"sell","state":"filled","filled_amount":"2","created_at":500000}
+			]}`)
+		case "/v2/orders/a/match-results":
+			matched["a"]++
+			fmt.Fprint(w, `{"status":0,"data":[{"price":"10","filled_amount":"1","fill_fees":"0.001","side":"buy","created_at":1500100}]}`)
+		case "/v2/orders/b/match-results":
+			matched["b"]++
+			fmt.Fprint(w, `{"status":0,"data":[
+				{"price":"11","filled_amount":"1","fill_fees":"0.011","side":"sell","created_at":600000},
+				{"price":"12","filled_amount":"1","fill_fees":"0.012","side":"sell","created_at":1200000}
+			]}`)
+		default:
+			t.Error("unexpected request", r.URL.Path)
+			fmt.Fprint(w, `{"status":1,"msg":"not found"}`)
+		}
+	}))
+	defer server.Close()
+
+	fs, _ := fcoin.NewFcoinService(server.URL, "key", "secret")
+	f := NewFcoin(fs)
+	from, to := time.Unix(1000, 0), time.Unix(2000, 0)
+	if fills, err := f.Fills(from, to); err != nil || len(fills) != 0 {
+		t.Fatal("no trade symbols should mean no fills", fills, err)
+	}
+
+	f.TradeSymbols = []string{"btcusdt"}
+	fills, err := f.Fills(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fills) != 2 || matched["a"] != 1 || matched["b"] != 1 {
+		t.Fatal(fills, matched)
+	}
+	if b := fills[0]; b.OrderID != "b" || b.Side != "sell" || b.Price != 12 || b.FeeCurrency != "usdt" || !b.Time.Equal(time.Unix(1200, 0)) {
+		t.Fatal(b)
+	}
+	if a := fills[1]; a.OrderID != "a" || a.Base != "btc" || a.Quote != "usdt" || a.FeeCurrency != "btc" || a.Fee != 0.001 {
+		t.Fatal(a)
+	}
+}
@@ -0,0 +1,273 @@
+package venue
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"go-exchange/gateio"
+	"go-exchange/impact"
+	"go-exchange/order"
+)
+
+// DefaultGateSlippage gate api2 没有市价单 以买一价下浮该比例挂限价卖单代替
+const DefaultGateSlippage = 0.05
+
+// GateFillWindow gate api2 只返回这段时间内的成交 更早的成交查不到
+const GateFillWindow = 24 * time.Hour
+
+// Gate gate api2 账户
+type Gate struct {
+	Service    *gateio.Service
+	Slippage   float64
+	TradePairs []string // api2 只能按交易对查询成交 Fills 查询这些交易对
+
+	mu    sync.Mutex
+	rules map[string]order.Rules
+}
+
+// NewGate 新建 gate 适配器
+func NewGate(s *gateio.Service) *Gate {
+	return &Gate{Service: s, Slippage: DefaultGateSlippage}
+}
+
+// Name 交易所名称
+func (g *Gate) Name() string {
+	return "gate"
+}
+
+// Rules 交易对下单规则 首次调用时查询并缓存
+func (g *Gate) Rules() (map[string]order.Rules, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.rules == nil {
+		info, err := g.Service.MarketInfo()
+		if err != nil {
+			return nil, err
+		}
+		g.rules = order.GateRules(info)
+	}
+	return g.rules, nil
+}
+
+// Balances 余额
+func (g *Gate) Balances() ([]Balance, error) {
+	res, err := g.Service.Balances()
+	if err != nil {
+		return nil, err
+	}
+	byCurrency := make(map[string]*Balance)
+	get := func(currency string) *Balance {
+		currency = strings.ToLower(currency)
+		b, ok := byCurrency[currency]
+		if !ok {
+			b = &Balance{Currency: currency}
+			byCurrency[currency] = b
+		}
+		return b
+	}
+	for currency, v := range res.Available {
+		f, err := parseFloat(v)
+		if err != nil {
+			return nil, err
+		}
+		get(currency).Available = f
+	}
+	for currency, v := range res.Locked {
+		f, err := parseFloat(v)
+		if err != nil {
+			return nil, err
+		}
+		get(currency).Frozen = f
+	}
+	balances := make([]Balance, 0, len(byCurrency))
+	for _, b := range byCurrency {
+		balances = append(balances, *b)
+	}
+	return balances, nil
+}
+
+// OpenOrders 所有交易对的挂单
+func (g *Gate) OpenOrders() ([]Order, error) {
+	list, err := g.Service.OpenOrders()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]Order, 0, len(list))
+	for _, o := range list {
+		res = append(res, Order{
+			Venue:  g.Name(),
+			ID:     o.OrderNumber.String(),
+			Pair:   o.CurrencyPair,
+			Side:   o.Type,
+			Price:  o.Rate.Float64(),
+			Amount: o.InitialAmount.Float64(),
+			Filled: o.FilledAmount.Float64(),
+		})
+	}
+	return res, nil
+}
+
+// CancelOrder 撤单
+func (g *Gate) CancelOrder(o Order) error {
+	return g.Service.CancelOrder(o.ID, o.Pair)
+}
+
+// MarketSell 以买一价下浮 Slippage 挂限价卖单 价格和数量按交易对规则取整
+func (g *Gate) MarketSell(base, quote string, amount float64) (string, error) {
+	rules, err := g.Rules()
+	if err != nil {
+		return "", err
+	}
+	pair := strings.ToLower(base + "_" + quote)
+	if _, ok := rules[pair]; !ok {
+		return "", ErrNoPair
+	}
+	book, err := g.Service.OrderBook(pair)
+	if err != nil {
+		return "", err
+	}
+	if len(book.Bids) == 0 {
+		return "", errors.New(pair + " has no bids")
+	}
+	price := book.Bids[0].Price * (1 - g.Slippage)
+	o, err := order.NewBuilder(rules).Limit(pair, order.Sell, price, amount)
+	if err != nil {
+		return "", err
+	}
+	res, err := g.Service.Sell(o.Gate())
+	if err != nil {
+		return "", err
+	}
+	return res.OrderNumber.String(), nil
+}
+
+// LastPrice 最新成交价
+func (g *Gate) LastPrice(base, quote string) (float64, error) {
+	rules, err := g.Rules()
+	if err != nil {
+		return 0, err
+	}
+	pair := strings.ToLower(base + "_" + quote)
+	if _, ok := rules[pair]; !ok {
+		return 0, ErrNoPair
+	}
+	t, err := g.Service.Ticker(pair)
+	if err != nil {
+		return 0, err
+	}
+	return t.Last.Float64(), nil
+}
+
+// Fills TradePairs 中各交易对的成交记录 没有设置 TradePairs 时没有成交
+// api2 只返回最近 GateFillWindow 内的成交 from 早于此时 更早的成交会缺失
+// 定期记录快照时间隔应小于 GateFillWindow
+func (g *Gate) Fills(from, to time.Time) ([]Fill, error) {
+	if len(g.TradePairs) == 0 {
+		return nil, nil
+	}
+	res := make([]Fill, 0)
+	for _, pair := range g.TradePairs {
+		trades, err := g.Service.MyTradeHistory(pair, "")
+		if err != nil {
+			return res, err
+		}
+		for _, t := range trades {
+			sec := t.TimeUnix.Float64()
+			at := time.Unix(int64(sec), int64((sec-float64(int64(sec)))*1e9))
+			if at.Before(from) || at.After(to) {
+				continue
+			}
+			p := strings.SplitN(strings.ToLower(t.Pair), "_", 2)
+			if len(p) != 2 {
+				p = strings.SplitN(strings.ToLower(pair), "_", 2)
+			}
+			if len(p) != 2 {
+				return res, errors.New("invalid gate pair " + pair)
+			}
+			res = append(res, Fill{
+				Venue:       g.Name(),
+				ID:          t.TradeID.String(),
+				OrderID:     t.OrderNumber.String(),
+				Base:        p[0],
+				Quote:       p[1],
+				Side:        t.Type,
+				Price:       t.Rate.Float64(),
+				Amount:      t.Amount.Float64(),
+				Fee:         t.Fee.Float64(),
+				FeeCurrency: strings.ToLower(t.FeeCoin),
+				Time:        at,
+			})
+		}
+	}
+	sortFills(res)
+	return res, nil
+}
+
+// OrderBook 深度
+func (g *Gate) OrderBook(base, quote string) (*impact.Book, error) {
+	book, err := g.Service.OrderBook(strings.ToLower(base + "_" + quote))
+	if err != nil {
+		return nil, err
+	}
+	return impact.FromGate(book)
+}
+
+// PairRules 交易对下单规则
+func (g *Gate) PairRules(base, quote string) (order.Rules, error) {
+	rules, err := g.Rules()
+	if err != nil {
+		return order.Rules{}, err
+	}
+	r, ok := rules[strings.ToLower(base+"_"+quote)]
+	if !ok {
+		return r, ErrNoPair
+	}
+	return r, nil
+}
+
+// PlaceLimit 下限价单
+func (g *Gate) PlaceLimit(base, quote, side string, price, amount float64) (string, error) {
+	rules, err := g.Rules()
+	if err != nil {
+		return "", err
+	}
+	pair := strings.ToLower(base + "_" + quote)
+	if _, ok := rules[pair]; !ok {
+		return "", ErrNoPair
+	}
+	o, err := order.NewBuilder(rules).Limit(pair, order.Side(side), price, amount)
+	if err != nil {
+		return "", err
+	}
+	var res *gateio.OrderResult
+	if o.Side == order.Buy {
+		res, err = g.Service.Buy(o.Gate())
+	} else {
+		res, err = g.Service.Sell(o.Gate())
+	}
+	if err != nil {
+		return "", err
+	}
+	return res.OrderNumber.String(), nil
+}
+
+// CancelLimit 撤销 PlaceLimit 下的订单
+func (g *Gate) CancelLimit(base, quote, id string) error {
+	return g.CancelOrder(Order{ID: id, Pair: strings.ToLower(base + "_" + quote)})
+}
+
+// OrderStatus 订单成交情况
+func (g *Gate) OrderStatus(base, quote, id string) (Execution, error) {
+	o, err := g.Service.GetOrder(id, strings.ToLower(base+"_"+quote))
+	if err != nil {
+		return Execution{}, err
+	}
+	return Execution{
+		ID:       id,
+		Filled:   o.FilledAmount.Float64(),
+		AvgPrice: o.FilledRate.Float64(),
+		Done:     o.Status == "closed" || o.Status == "cancelled",
+	}, nil
+}
@@ -0,0 +1,14 @@
+package venue
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGateFillsWithoutPairs(t *testing.T) {
+	g := NewGate(nil)
+	fills, err := g.Fills(time.Now().Add(-time.Hour), time.Now())
+	if err != nil || len(fills) != 0 {
+		t.Fatal("no trade pairs should mean no fills", fills, err)
+	}
+}
@@ -0,0 +1,164 @@
+package venue
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"go-exchange/batch"
+)
+
+// KillSwitch 撤销所有交易所的全部挂单 可选把其余币种市价卖成 Target
+// 每个交易所并发处理 单个失败不影响其他操作 结果全部记录在 Report 中
+type KillSwitch struct {
+	Venues  []Venue
+	Flatten bool          // 撤单后把余额卖成 Target
+	Target  string        // 卖出的目标币种 如 usdt
+	Keep    []string      // 不卖出的币种
+	DryRun  bool          // 只列出将要执行的操作
+	Options batch.Options // 每个交易所撤单的并发与限速 FailFast 不生效
+}
+
+// OrderFailure 撤单失败
+type OrderFailure struct {
+	Order Order
+	Err   error
+}
+
+// Sale 卖出 DryRun 时 OrderID 为空
+type Sale struct {
+	Currency string
+	Amount   float64
+	OrderID  string
+}
+
+// SaleFailure 卖出失败
+type SaleFailure struct {
+	Currency string
+	Amount   float64
+	Err      error
+}
+
+// VenueReport 单个交易所的执行结果
+type VenueReport struct {
+	Venue        string
+	Err          error // 查询挂单或余额失败
+	Canceled     []Order
+	CancelFailed []OrderFailure
+	Sold         []Sale
+	SellFailed   []SaleFailure
+}
+
+// OK 全部成功
+func (r *VenueReport) OK() bool {
+	return r.Err == nil && len(r.CancelFailed) == 0 && len(r.SellFailed) == 0
+}
+
+// Report 所有交易所的执行结果 顺序与 KillSwitch.Venues 一致
+type Report struct {
+	DryRun bool
+	Venues []VenueReport
+}
+
+// OK 全部成功
+func (r *Report) OK() bool {
+	for i := range r.Venues {
+		if !r.Venues[i].OK() {
+			return false
+		}
+	}
+	return true
+}
+
+// Run 执行
+func (k *KillSwitch) Run() *Report {
+	report := &Report{DryRun: k.DryRun, Venues: make([]VenueReport, len(k.Venues))}
+	batch.Run(len(k.Venues), batch.Options{Concurrency: len(k.Venues)}, func(i int) error {
+		report.Venues[i] = k.runVenue(k.Venues[i])
+		return nil
+	})
+	return report
+}
+
+func (k *KillSwitch) runVenue(v Venue) VenueReport {
+	r := VenueReport{Venue: v.Name()}
+	orders, err := v.OpenOrders()
+	if err != nil {
+		// 部分交易对已查到的挂单仍然撤销
+		r.Err = fmt.Errorf("open orders: %v", err)
+	}
+	if k.DryRun {
+		r.Canceled = orders
+	} else {
+		opts := k.Options
+		opts.FailFast = false
+		errs := batch.Run(len(orders), opts, func(i int) error {
+			return v.CancelOrder(orders[i])
+		})
+		for i, err := range errs {
+			if err != nil {
+				r.CancelFailed = append(r.CancelFailed, OrderFailure{Order: orders[i], Err: err})
+			} else {
+				r.Canceled = append(r.Canceled, orders[i])
+			}
+		}
+	}
+	if !k.Flatten || r.Err != nil {
+		return r
+	}
+
+	// 撤单后冻结的余额才会释放 重新查询
+	balances, err := v.Balances()
+	if err != nil {
+		r.Err = fmt.Errorf("balances: %v", err)
+		return r
+	}
+	target := strings.ToLower(k.Target)
+	keep := make(map[string]bool, len(k.Keep))
+	for _, c := range k.Keep {
+		keep[strings.ToLower(c)] = true
+	}
+	for _, b := range balances {
+		if b.Currency == target || keep[b.Currency] || (b.Available <= 0 && !(k.DryRun && b.Total() > 0)) {
+			continue
+		}
+		if k.DryRun {
+			// 撤单后冻结部分也会释放
+			r.Sold = append(r.Sold, Sale{Currency: b.Currency, Amount: b.Total()})
+			continue
+		}
+		id, err := v.MarketSell(b.Currency, target, b.Available)
+		if err != nil {
+			r.SellFailed = append(r.SellFailed, SaleFailure{Currency: b.Currency, Amount: b.Available, Err: err})
+			continue
+		}
+		r.Sold = append(r.Sold, Sale{Currency: b.Currency, Amount: b.Available, OrderID: id})
+	}
+	return r
+}
+
+// Write 输出可读的报告
+func (r *Report) Write(w io.Writer) {
+	prefix := ""
+	if r.DryRun {
+		prefix = "[dry-run] "
+	}
+	for _, v := range r.Venues {
+		fmt.Fprintf(w, "%s%s:\n", prefix, v.Venue)
+		if v.Err != nil {
+			fmt.Fprintf(w, "  error: %v\n", v.Err)
+		}
+		for _, o := range v.Canceled {
+			fmt.Fprintf(w, "  canceled %s %s %s %v@%v\n", o.Pair, o.Side, o.ID, o.Amount, o.Price)
+		}
+		for _, f := range v.CancelFailed {
+			fmt.Fprintf(w, "  cancel failed %s %s %s: %v\n", f.Order.Pair, f.Order.Side, f.Order.ID, f.Err)
+		}
+		for _, s := range v.Sold {
+			fmt.Fprintf(w, "  sold %v %s order %s\n", s.Amount, s.Currency, s.OrderID)
+		}
+		for _, f := range v.SellFailed {
+			fmt.Fprintf(w, "  sell failed %v %s: %v\n", f.Amount, f.Currency, f.Err)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package venue
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeVenue 内存中的交易所 撤单后释放冻结余额
+type fakeVenue struct {
+	name     string
+	mu       sync.Mutex
+	orders   []Order
+	balances map[string]*Balance
+	failOn   string // 撤单失败的订单 ID
+	noPair   string // 没有交易对的币种
+	canceled []string
+	sold     map[string]float64
+}
+
+func (f *fakeVenue) Name() string { return f.name }
+
+func (f *fakeVenue) Balances() ([]Balance, error) {
+	res := make([]Balance, 0)
+	for _, b := range f.balances {
+		res = append(res, *b)
+	}
+	return res, nil
+}
+
+func (f *fakeVenue) OpenOrders() ([]Order, error) { return f.orders, nil }
+
+func (f *fakeVenue) CancelOrder(o Order) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if o.ID == f.failOn {
+		return errors.New("rejected")
+	}
+	f.canceled = append(f.canceled, o.ID)
+	base := strings.Split(o.Pair, "_")[0]
+	if b := f.balances[base]; b != nil {
+		b.Available += b.Frozen
+		b.Frozen = 0
+	}
+	return nil
+}
+
+func (f *fakeVenue) MarketSell(base, quote string, amount float64) (string, error) {
+	if base == f.noPair {
+		return "", ErrNoPair
+	}
+	f.sold[base] += amount
+	return "sell-" + base, nil
+}
+
+func TestKillSwitch(t *testing.T) {
+	a := &fakeVenue{
+		name: "a",
+		orders: []Order{
+			{ID: "1", Pair: "eth_usdt", Side: "sell"},
+			{ID: "2", Pair: "eth_usdt", Side: "sell"},
+		},
+		balances: map[string]*Balance{
+			"eth":  {Currency: "eth", Available: 1, Frozen: 2},
+			"usdt": {Currency: "usdt", Available: 100},
+			"btc":  {Currency: "btc", Available: 0.5},
+			"xyz":  {Currency: "xyz", Available: 9},
+		},
+		noPair: "xyz",
+		sold:   make(map[string]float64),
+	}
+	b := &fakeVenue{
+		name:     "b",
+		orders:   []Order{{ID: "9", Pair: "ft_usdt", Side: "buy"}},
+		balances: map[string]*Balance{"ft": {Currency: "ft", Available: 10}},
+		failOn:   "9",
+		sold:     make(map[string]float64),
+	}
+
+	ks := &KillSwitch{Venues: []Venue{a, b}, Flatten: true, Target: "USDT", Keep: []string{"btc"}}
+	report := ks.Run()
+	if report.OK() {
+		t.Fatal("venue b cancel and venue a xyz sale failed")
+	}
+	ra, rb := report.Venues[0], report.Venues[1]
+	if ra.Venue != "a" || len(ra.Canceled) != 2 || len(ra.CancelFailed) != 0 {
+		t.Fatal(ra)
+	}
+	// 撤单释放的 2 eth 也一起卖出 btc 保留 usdt 为目标币种
+	if a.sold["eth"] != 3 || a.sold["btc"] != 0 || a.sold["usdt"] != 0 {
+		t.Fatal(a.sold)
+	}
+	if len(ra.SellFailed) != 1 || ra.SellFailed[0].Err != ErrNoPair {
+		t.Fatal(ra.SellFailed)
+	}
+	if len(rb.CancelFailed) != 1 || rb.CancelFailed[0].Order.ID != "9" || b.sold["ft"] != 10 {
+		t.Fatal(rb, b.sold)
+	}
+
+	var buf bytes.Buffer
+	report.Write(&buf)
+	if !strings.Contains(buf.String(), "cancel failed ft_usdt buy 9: rejected") {
+		t.Fatal(buf.String())
+	}
+}
+
+func TestKillSwitchDryRun(t *testing.T) {
+	a := &fakeVenue{
+		name:     "a",
+		orders:   []Order{{ID: "1", Pair: "eth_usdt"}},
+		balances: map[string]*Balance{"eth": {Currency: "eth", Available: 1}},
+		sold:     make(map[string]float64),
+	}
+	report := (&KillSwitch{Venues: []Venue{a}, Flatten: true, Target: "usdt", DryRun: true}).Run()
+	if len(a.canceled) != 0 || len(a.sold) != 0 {
+		t.Fatal("dry run touched the venue")
+	}
+	r := report.Venues[0]
+	if len(r.Canceled) != 1 || len(r.Sold) != 1 || r.Sold[0].Amount != 1 {
+		t.Fatal(r)
+	}
+}
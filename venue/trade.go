@@ -0,0 +1,39 @@
+package venue
+
+import (
+	"go-exchange/impact"
+	"go-exchange/order"
+)
+
+// Depth 可选接口 查询交易对深度 没有该交易对时返回 ErrNoPair
+type Depth interface {
+	OrderBook(base, quote string) (*impact.Book, error)
+}
+
+// Trader 可选接口 下限价单 价格和数量按交易所规则取整 返回订单 ID
+type Trader interface {
+	PlaceLimit(base, quote, side string, price, amount float64) (string, error)
+}
+
+// Canceler 可选接口 撤销 Trader 下的订单
+type Canceler interface {
+	CancelLimit(base, quote, id string) error
+}
+
+// Precision 可选接口 Trader 下单时取整使用的交易对规则
+type Precision interface {
+	PairRules(base, quote string) (order.Rules, error)
+}
+
+// Execution 订单的成交情况
+type Execution struct {
+	ID       string
+	Filled   float64 // 已成交的基准货币数量
+	AvgPrice float64 // 成交均价 未成交时为 0
+	Done     bool    // 已完全成交或已撤销
+}
+
+// OrderStatus 可选接口 查询 Trader 下的订单
+type OrderStatus interface {
+	OrderStatus(base, quote, id string) (Execution, error)
+}
@@ -0,0 +1,90 @@
+package venue
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNoPair 交易所没有该交易对
+var ErrNoPair = errors.New("pair not listed")
+
+// Balance 单个币种的余额 币种统一为小写
+type Balance struct {
+	Currency  string  `json:"currency"`
+	Available float64 `json:"available"`
+	Frozen    float64 `json:"frozen"`
+}
+
+// Total 可用加冻结
+func (b Balance) Total() float64 {
+	return b.Available + b.Frozen
+}
+
+// Order 挂单 Pair 为交易所中的交易对名称 Side 为 buy 或 sell
+type Order struct {
+	Venue  string
+	ID     string
+	Pair   string
+	Side   string
+	Price  float64
+	Amount float64
+	Filled float64
+}
+
+// Venue 交易所账户适配器 屏蔽各交易所接口的差异
+type Venue interface {
+	// Name 交易所名称 如 fcoin
+	Name() string
+	// Balances 所有余额
+	Balances() ([]Balance, error)
+	// OpenOrders 所有未完成的挂单
+	OpenOrders() ([]Order, error)
+	// CancelOrder 撤销 OpenOrders 返回的挂单
+	CancelOrder(o Order) error
+	// MarketSell 把 amount 数量的 base 市价卖成 quote 返回订单 ID 没有该交易对时返回 ErrNoPair
+	MarketSell(base, quote string, amount float64) (string, error)
+}
+
+// Ticker 可选接口 查询交易对最新成交价 没有该交易对时返回 ErrNoPair
+type Ticker interface {
+	LastPrice(base, quote string) (float64, error)
+}
+
+// Fill 成交 币种统一为小写 手续费可能是基准 计价或第三种币
+type Fill struct {
+	Venue       string    `json:"venue"`
+	ID          string    `json:"id"`
+	OrderID     string    `json:"order_id"`
+	Base        string    `json:"base"`
+	Quote       string    `json:"quote"`
+	Side        string    `json:"side"` // buy sell
+	Price       float64   `json:"price"`
+	Amount      float64   `json:"amount"` // 基准货币数量
+	Fee         float64   `json:"fee"`
+	FeeCurrency string    `json:"fee_currency"`
+	Time        time.Time `json:"time"`
+}
+
+// FillHistory 可选接口 查询 [from, to] 时间范围内的成交 按时间从旧到新
+type FillHistory interface {
+	Fills(from, to time.Time) ([]Fill, error)
+}
+
+// sortFills 按时间从旧到新
+func sortFills(fills []Fill) {
+	sort.SliceStable(fills, func(i, j int) bool {
+		return fills[i].Time.Before(fills[j].Time)
+	})
+}
+
+// parseFloat 解析接口中的数值字符串 空字符串为 0
+func parseFloat(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(s, 64)
+}